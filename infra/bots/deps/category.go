@@ -0,0 +1,172 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"path"
+
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+	"go.skia.org/skia/infra/bots/deps/gen"
+)
+
+// IsTooling returns true if the given entry provides build or CI tooling rather
+// than a library. See gen.IsTooling.
+func IsTooling(e deps_parser.DepsEntry) bool {
+	return gen.IsTooling(e)
+}
+
+// ExperimentalPaths are the Paths of dependencies which are considered
+// experimental in Skia.
+var ExperimentalPaths = map[string]bool{
+	"third_party/externals/imgui": true,
+	"third_party/externals/vello": true,
+}
+
+// Experimental returns the dependencies, sorted by ID, whose Path is listed in
+// ExperimentalPaths.
+func Experimental() []deps_parser.DepsEntry {
+	return filterEntries(deps, func(e deps_parser.DepsEntry) bool {
+		return ExperimentalPaths[e.Path]
+	})
+}
+
+// Stable returns the dependencies, sorted by ID, whose Path is not listed in
+// ExperimentalPaths.
+func Stable() []deps_parser.DepsEntry {
+	return filterEntries(deps, func(e deps_parser.DepsEntry) bool {
+		return !ExperimentalPaths[e.Path]
+	})
+}
+
+// SupportOnlyPaths are the Paths of dependencies which Skia does not use
+// directly, but which exist to supply other dependencies or build tooling, eg.
+// umbrella repositories and API registries.
+var SupportOnlyPaths = map[string]bool{
+	"third_party/externals/egl-registry":    true,
+	"third_party/externals/opengl-registry": true,
+	"third_party/externals/vulkan-deps":     true,
+}
+
+// SupportOnly returns the dependencies, sorted by ID, whose Path is listed in
+// SupportOnlyPaths.
+func SupportOnly() []deps_parser.DepsEntry {
+	return filterEntries(deps, func(e deps_parser.DepsEntry) bool {
+		return SupportOnlyPaths[e.Path]
+	})
+}
+
+// GitRevisionCIPDEntries returns the dependencies, sorted by ID, which are
+// IsGitRevisionCIPD.
+func GitRevisionCIPDEntries() []deps_parser.DepsEntry {
+	return filterEntries(deps, IsGitRevisionCIPD)
+}
+
+// filterEntries returns the entries, sorted by ID, for which keep returns true.
+func filterEntries(entries deps_parser.DepsEntries, keep func(deps_parser.DepsEntry) bool) []deps_parser.DepsEntry {
+	var rv []deps_parser.DepsEntry
+	for _, e := range sortedEntries(entries) {
+		if keep(e) {
+			rv = append(rv, e)
+		}
+	}
+	return rv
+}
+
+// UnscannedLicenses returns the library entries, sorted by ID, whose Path is not
+// in the given list of license-scanned paths. Tooling is ignored.
+func UnscannedLicenses(entries deps_parser.DepsEntries, scanned []string) []deps_parser.DepsEntry {
+	isScanned := make(map[string]bool, len(scanned))
+	for _, p := range scanned {
+		isScanned[p] = true
+	}
+	var rv []deps_parser.DepsEntry
+	for _, e := range sortedEntries(entries) {
+		if !IsTooling(e) && !isScanned[e.Path] {
+			rv = append(rv, e)
+		}
+	}
+	return rv
+}
+
+// UnknownLicense is the GroupByLicense bucket for entries with no known license.
+const UnknownLicense = "UNKNOWN"
+
+// GroupByLicense returns the given entries, each group sorted by ID, keyed by
+// the SPDX license identifier which the given map assigns to their Path.
+// Entries whose Path is not in the map are grouped under UnknownLicense.
+func GroupByLicense(entries deps_parser.DepsEntries, licenses map[string]string) map[string][]deps_parser.DepsEntry {
+	rv := map[string][]deps_parser.DepsEntry{}
+	for _, e := range sortedEntries(entries) {
+		license, ok := licenses[e.Path]
+		if !ok {
+			license = UnknownLicense
+		}
+		rv[license] = append(rv[license], e)
+	}
+	return rv
+}
+
+// Category describes the role of a dependency in the Skia checkout.
+type Category int
+
+const (
+	// CategoryUnknown indicates that the dependency's Path matches none of
+	// categoryPaths.
+	CategoryUnknown Category = iota
+	// CategoryLibrary indicates a third-party library.
+	CategoryLibrary
+	// CategoryTooling indicates build or CI tooling.
+	CategoryTooling
+	// CategoryInfra indicates Skia infrastructure code.
+	CategoryInfra
+)
+
+// String implements fmt.Stringer.
+func (c Category) String() string {
+	switch c {
+	case CategoryLibrary:
+		return "library"
+	case CategoryTooling:
+		return "tooling"
+	case CategoryInfra:
+		return "infra"
+	default:
+		return "unknown"
+	}
+}
+
+// categoryPaths maps directories to the Category of the dependencies synced
+// into them.
+var categoryPaths = map[string]Category{
+	externalsDir:   CategoryLibrary,
+	"bin":          CategoryTooling,
+	"buildtools":   CategoryTooling,
+	"task_drivers": CategoryTooling,
+	"infra":        CategoryInfra,
+}
+
+// CategorizeByPath returns the Category of the given entry according to the
+// deepest directory in categoryPaths which contains its Path.
+func CategorizeByPath(e deps_parser.DepsEntry) Category {
+	for dir := e.Path; dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if c, ok := categoryPaths[dir]; ok {
+			return c
+		}
+	}
+	return CategoryUnknown
+}
+
+// Uncategorized returns the dependencies, sorted by ID, whose Category is
+// CategoryUnknown.
+func Uncategorized() []deps_parser.DepsEntry {
+	return uncategorized(deps)
+}
+
+func uncategorized(entries deps_parser.DepsEntries) []deps_parser.DepsEntry {
+	return filterEntries(entries, func(e deps_parser.DepsEntry) bool {
+		return CategorizeByPath(e) == CategoryUnknown
+	})
+}
@@ -0,0 +1,124 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+func TestIsTooling(t *testing.T) {
+	var tooling []string
+	for _, e := range filterEntries(deps, IsTooling) {
+		tooling = append(tooling, e.Id)
+	}
+	assert.Equal(t, []string{
+		"chromium.googlesource.com/chromium/src/buildtools",
+		"infra/3pp/tools/ninja",
+		"skia/tools/bazel_build",
+		"skia/tools/sk",
+	}, tooling)
+	assert.True(t, IsTooling(*deps["infra/3pp/tools/ninja"]))
+	assert.False(t, IsTooling(*deps["chromium.googlesource.com/chromium/deps/icu"]))
+}
+
+func TestUnscannedLicenses_PartialScan_ReturnsGaps(t *testing.T) {
+	entries := deps_parser.DepsEntries{
+		"a":                     {Id: "a", Path: "third_party/externals/a"},
+		"b":                     {Id: "b", Path: "third_party/externals/b"},
+		"c":                     {Id: "c", Path: "third_party/externals/c"},
+		"infra/3pp/tools/ninja": {Id: "infra/3pp/tools/ninja", Path: "bin"},
+	}
+	unscanned := UnscannedLicenses(entries, []string{"third_party/externals/b"})
+	var ids []string
+	for _, e := range unscanned {
+		ids = append(ids, e.Id)
+	}
+	assert.Equal(t, []string{"a", "c"}, ids)
+}
+
+func TestExperimental_DefaultSet(t *testing.T) {
+	containsId := func(entries []deps_parser.DepsEntry, id string) bool {
+		for _, e := range entries {
+			if e.Id == id {
+				return true
+			}
+		}
+		return false
+	}
+	const vello = "skia.googlesource.com/external/github.com/linebender/vello"
+	const harfbuzz = "chromium.googlesource.com/external/github.com/harfbuzz/harfbuzz"
+	assert.True(t, containsId(Experimental(), vello))
+	assert.False(t, containsId(Stable(), vello))
+	assert.False(t, containsId(Experimental(), harfbuzz))
+	assert.True(t, containsId(Stable(), harfbuzz))
+	assert.Len(t, Experimental(), 2)
+	assert.Len(t, Stable(), len(deps)-2)
+}
+
+func TestSupportOnly_DefaultSet(t *testing.T) {
+	var paths []string
+	for _, e := range SupportOnly() {
+		paths = append(paths, e.Path)
+	}
+	assert.Contains(t, paths, "third_party/externals/egl-registry")
+	assert.Contains(t, paths, "third_party/externals/opengl-registry")
+	assert.Contains(t, paths, "third_party/externals/vulkan-deps")
+	assert.NotContains(t, paths, "third_party/externals/libpng")
+	assert.Len(t, paths, len(SupportOnlyPaths))
+}
+
+func TestGitRevisionCIPDEntries(t *testing.T) {
+	var ids []string
+	for _, e := range GitRevisionCIPDEntries() {
+		ids = append(ids, e.Id)
+	}
+	assert.Equal(t, []string{"skia/tools/bazel_build", "skia/tools/sk"}, ids)
+}
+
+func TestGroupByLicense_PartialMap(t *testing.T) {
+	groups := GroupByLicense(deps, map[string]string{
+		"third_party/externals/harfbuzz": "MIT",
+		"third_party/externals/libpng":   "libpng-2.0",
+		"third_party/externals/icu":      "Unicode-3.0",
+	})
+	require.Len(t, groups["MIT"], 1)
+	assert.Equal(t, "chromium.googlesource.com/external/github.com/harfbuzz/harfbuzz", groups["MIT"][0].Id)
+	assert.Len(t, groups["libpng-2.0"], 1)
+	assert.Len(t, groups["Unicode-3.0"], 1)
+	assert.Len(t, groups[UnknownLicense], len(deps)-3)
+	assert.Len(t, groups, 4)
+}
+
+func TestCategorizeByPath(t *testing.T) {
+	test := func(path string, expect Category) {
+		assert.Equal(t, expect, CategorizeByPath(deps_parser.DepsEntry{Path: path}), path)
+	}
+	test("third_party/externals/harfbuzz", CategoryLibrary)
+	test("third_party/externals/c/nested", CategoryLibrary)
+	test("bin", CategoryTooling)
+	test("task_drivers", CategoryTooling)
+	test("infra/skia-infra", CategoryInfra)
+	test("third_party/other", CategoryUnknown)
+	test("binaries", CategoryUnknown)
+	test("", CategoryUnknown)
+	test("/abs", CategoryUnknown)
+}
+
+func TestUncategorized(t *testing.T) {
+	assert.Empty(t, Uncategorized())
+
+	entries := deps_parser.DepsEntries{
+		"chromium.googlesource.com/external/github.com/harfbuzz/harfbuzz": deps["chromium.googlesource.com/external/github.com/harfbuzz/harfbuzz"],
+		"example.googlesource.com/dep":                                    {Id: "example.googlesource.com/dep", Version: testHash, Path: "third_party/unrecognized/dep"},
+	}
+	unknown := uncategorized(entries)
+	require.Len(t, unknown, 1)
+	assert.Equal(t, "example.googlesource.com/dep", unknown[0].Id)
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// checkgen verifies that the generated deps_gen.go is up to date with DEPS.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"go.skia.org/skia/infra/bots/deps/gen"
+)
+
+func main() {
+	var (
+		depsFile = flag.String("deps_file", "DEPS", "The location of the DEPS file.")
+		genFile  = flag.String("gen_file", "infra/bots/deps/deps_gen.go", "The location of the generated Go file.")
+		semantic = flag.Bool("semantic", false, "Compare the entries declared in the generated file with DEPS, ignoring ordering and formatting.")
+	)
+	flag.Parse()
+
+	b, err := os.ReadFile(*depsFile)
+	if err != nil {
+		fmt.Printf("Could not read %s: %s\n", *depsFile, err)
+		os.Exit(1)
+	}
+	parsed, err := gen.ParseDeps(string(b))
+	if err != nil {
+		fmt.Printf("Could not parse %s: %s\n", *depsFile, err)
+		os.Exit(1)
+	}
+
+	actual, err := os.ReadFile(*genFile)
+	if err != nil {
+		fmt.Printf("Could not read %s: %s\n", *genFile, err)
+		os.Exit(1)
+	}
+
+	if *semantic {
+		generated, err := gen.ParseGeneratedSource(actual)
+		if err != nil {
+			fmt.Printf("Could not parse %s: %s\n", *genFile, err)
+			os.Exit(1)
+		}
+		if !gen.SemanticallyEqual(parsed, generated) {
+			fmt.Printf("%s does not match %s; regenerate it with `go generate`.\n", *genFile, *depsFile)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if gen.HasTrailingWhitespace(actual) {
+		fmt.Printf("%s contains trailing whitespace.\n", *genFile)
+		os.Exit(1)
+	}
+	var expect bytes.Buffer
+	if err := gen.Generate(&expect, parsed, gen.DepsGenOptions); err != nil {
+		fmt.Printf("Could not generate code: %s\n", err)
+		os.Exit(1)
+	}
+	if !bytes.Equal(expect.Bytes(), actual) {
+		fmt.Printf("%s is out of date with %s; regenerate it with `go generate`.\n", *genFile, *depsFile)
+		os.Exit(1)
+	}
+}
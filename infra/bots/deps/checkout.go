@@ -0,0 +1,65 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+	"go.skia.org/infra/go/skerr"
+)
+
+// VerifyNoSymlinkOverlap inspects the checkout at root and returns the pairs of
+// dependencies, each sorted by ID, whose Paths resolve to the same or nested
+// directories because of symlinks, which may cause gclient to corrupt the
+// tree. Dependencies which are not present on disk are ignored, as are
+// dependencies which share a Path or are nested in the manifest itself.
+func VerifyNoSymlinkOverlap(root string) ([][2]deps_parser.DepsEntry, error) {
+	return verifyNoSymlinkOverlap(root, deps)
+}
+
+func verifyNoSymlinkOverlap(root string, entries deps_parser.DepsEntries) ([][2]deps_parser.DepsEntry, error) {
+	type resolvedEntry struct {
+		entry    deps_parser.DepsEntry
+		resolved string
+	}
+	var resolved []resolvedEntry
+	for _, e := range sortedEntries(entries) {
+		resolvedPath, err := filepath.EvalSymlinks(filepath.Join(root, filepath.FromSlash(e.Path)))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, skerr.Wrapf(err, "resolving path of %s", e.Id)
+		}
+		resolved = append(resolved, resolvedEntry{entry: e, resolved: resolvedPath})
+	}
+
+	var rv [][2]deps_parser.DepsEntry
+	for i, a := range resolved {
+		for _, b := range resolved[i+1:] {
+			if pathsOverlap(a.entry.Path, b.entry.Path) {
+				continue
+			}
+			if pathsOverlap(a.resolved, b.resolved) {
+				rv = append(rv, [2]deps_parser.DepsEntry{a.entry, b.entry})
+			}
+		}
+	}
+	return rv, nil
+}
+
+// pathsOverlap returns true if the given paths are the same or one is inside
+// the other.
+func pathsOverlap(a, b string) bool {
+	nested := func(parent, child string) bool {
+		return strings.HasPrefix(child, strings.TrimSuffix(parent, "/")+"/")
+	}
+	a, b = filepath.ToSlash(a), filepath.ToSlash(b)
+	return a == b || nested(a, b) || nested(b, a)
+}
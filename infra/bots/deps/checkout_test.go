@@ -0,0 +1,47 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+func TestVerifyNoSymlinkOverlap(t *testing.T) {
+	root := t.TempDir()
+	mkdir := func(path string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, filepath.FromSlash(path)), 0755))
+	}
+	mkdir("third_party/externals/a")
+	mkdir("third_party/externals/c")
+	mkdir("third_party/externals/c/nested")
+	// b is a symlink to a.
+	require.NoError(t, os.Symlink(filepath.Join(root, "third_party", "externals", "a"), filepath.Join(root, "third_party", "externals", "b")))
+
+	entries := deps_parser.DepsEntries{
+		"a":       {Id: "a", Version: testHash, Path: "third_party/externals/a"},
+		"b":       {Id: "b", Version: testHash, Path: "third_party/externals/b"},
+		"c":       {Id: "c", Version: testHash, Path: "third_party/externals/c"},
+		"nested":  {Id: "nested", Version: testHash, Path: "third_party/externals/c/nested"},
+		"missing": {Id: "missing", Version: testHash, Path: "third_party/externals/missing"},
+	}
+	overlaps, err := verifyNoSymlinkOverlap(root, entries)
+	require.NoError(t, err)
+	require.Len(t, overlaps, 1)
+	assert.Equal(t, "a", overlaps[0][0].Id)
+	assert.Equal(t, "b", overlaps[0][1].Id)
+}
+
+func TestVerifyNoSymlinkOverlap_NoSymlinks_Empty(t *testing.T) {
+	overlaps, err := VerifyNoSymlinkOverlap(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, overlaps)
+}
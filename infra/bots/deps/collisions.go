@@ -0,0 +1,200 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+// BasenameCollisions returns the Path basenames which are used by more than one
+// dependency, mapped to the entries using them, sorted by ID.
+func BasenameCollisions() map[string][]deps_parser.DepsEntry {
+	return basenameCollisions(deps)
+}
+
+func basenameCollisions(entries deps_parser.DepsEntries) map[string][]deps_parser.DepsEntry {
+	byBasename := map[string][]deps_parser.DepsEntry{}
+	for _, e := range sortedEntries(entries) {
+		base := path.Base(e.Path)
+		byBasename[base] = append(byBasename[base], e)
+	}
+	for base, group := range byBasename {
+		if len(group) < 2 {
+			delete(byBasename, base)
+		}
+	}
+	return byBasename
+}
+
+// CaseInsensitivePathCollisions returns groups of dependencies, each sorted by
+// ID, whose Paths differ only in case and would therefore collide on a
+// case-insensitive filesystem, eg. the default on macOS.
+func CaseInsensitivePathCollisions() [][]deps_parser.DepsEntry {
+	return caseInsensitivePathCollisions(deps)
+}
+
+func caseInsensitivePathCollisions(entries deps_parser.DepsEntries) [][]deps_parser.DepsEntry {
+	byLower := map[string][]deps_parser.DepsEntry{}
+	for _, e := range sortedEntries(entries) {
+		lower := strings.ToLower(e.Path)
+		byLower[lower] = append(byLower[lower], e)
+	}
+	var rv [][]deps_parser.DepsEntry
+	for _, group := range byLower {
+		for _, e := range group[1:] {
+			if e.Path != group[0].Path {
+				rv = append(rv, group)
+				break
+			}
+		}
+	}
+	sort.Slice(rv, func(i, j int) bool {
+		return rv[i][0].Id < rv[j][0].Id
+	})
+	return rv
+}
+
+// SlugCollisions returns the Slugs which are shared by more than one
+// dependency, mapped to the entries using them, sorted by ID.
+func SlugCollisions() map[string][]deps_parser.DepsEntry {
+	return slugCollisions(deps)
+}
+
+func slugCollisions(entries deps_parser.DepsEntries) map[string][]deps_parser.DepsEntry {
+	bySlug := map[string][]deps_parser.DepsEntry{}
+	for _, e := range sortedEntries(entries) {
+		slug := Slug(e)
+		bySlug[slug] = append(bySlug[slug], e)
+	}
+	for slug, group := range bySlug {
+		if len(group) < 2 {
+			delete(bySlug, slug)
+		}
+	}
+	return bySlug
+}
+
+// SharedSHAAllowlist lists groups of IDs which are expected to be pinned to the
+// same git revision, and so are ignored by SuspiciousSharedSHAs.
+var SharedSHAAllowlist = [][]string{
+	// sk is built from the buildbot repo at the pinned infra revision.
+	{"skia.googlesource.com/buildbot", "skia/tools/sk"},
+}
+
+// SuspiciousSharedSHAs returns groups of dependencies, each sorted by ID, which
+// are pinned to the same git revision, which usually indicates a copy-paste
+// error. CIPD packages built at a git revision are included. Groups contained
+// within an entry of SharedSHAAllowlist are ignored.
+func SuspiciousSharedSHAs() [][]deps_parser.DepsEntry {
+	return suspiciousSharedSHAs(deps, SharedSHAAllowlist)
+}
+
+func suspiciousSharedSHAs(entries deps_parser.DepsEntries, allowlist [][]string) [][]deps_parser.DepsEntry {
+	byHash := map[string][]deps_parser.DepsEntry{}
+	for _, e := range sortedEntries(entries) {
+		hash := strings.ToLower(strings.TrimPrefix(e.Version, gitRevisionPrefix))
+		if ClassifyVersion(hash) == VersionGit {
+			byHash[hash] = append(byHash[hash], e)
+		}
+	}
+	var rv [][]deps_parser.DepsEntry
+	for _, group := range byHash {
+		if len(group) > 1 && !allowedGroup(group, allowlist) {
+			rv = append(rv, group)
+		}
+	}
+	sort.Slice(rv, func(i, j int) bool {
+		return rv[i][0].Id < rv[j][0].Id
+	})
+	return rv
+}
+
+// allowedGroup returns true if every entry in the group is listed in the same
+// entry of the allowlist.
+func allowedGroup(group []deps_parser.DepsEntry, allowlist [][]string) bool {
+	for _, allowed := range allowlist {
+		ids := make(map[string]bool, len(allowed))
+		for _, id := range allowed {
+			ids[id] = true
+		}
+		all := true
+		for _, e := range group {
+			all = all && ids[e.Id]
+		}
+		if all {
+			return true
+		}
+	}
+	return false
+}
+
+// CrossCaseRisk returns the entries, sorted by ID, whose Path differs only in
+// case from another entry's Path, or whose Path basename differs only in case
+// from the basename of another entry's ID. Such entries may collide with their
+// siblings on case-insensitive filesystems, or be confused for them.
+func CrossCaseRisk(entries deps_parser.DepsEntries) []deps_parser.DepsEntry {
+	sorted := sortedEntries(entries)
+	differsInCase := func(a, b string) bool {
+		return a != b && strings.EqualFold(a, b)
+	}
+	return filterEntries(entries, func(e deps_parser.DepsEntry) bool {
+		for _, other := range sorted {
+			if other.Id == e.Id {
+				continue
+			}
+			if differsInCase(e.Path, other.Path) || differsInCase(path.Base(e.Path), path.Base(other.Id)) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// NearDuplicateIds returns pairs of IDs, each sorted and the pairs sorted in
+// turn, which are within maxDistance edits of each other and so may be typos of
+// one another, eg. "harfbzz" and "harfbuzz".
+func NearDuplicateIds(entries deps_parser.DepsEntries, maxDistance int) [][2]string {
+	ids := make([]string, 0, len(entries))
+	for id := range entries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var rv [][2]string
+	for i, a := range ids {
+		for _, b := range ids[i+1:] {
+			if levenshtein(a, b) <= maxDistance {
+				rv = append(rv, [2]string{a, b})
+			}
+		}
+	}
+	return rv
+}
+
+// levenshtein returns the number of single-byte insertions, deletions and
+// substitutions needed to turn a into b.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,123 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+func TestBasenameCollisions_SharedBasename_Grouped(t *testing.T) {
+	entries := deps_parser.DepsEntries{
+		"a": {Id: "a", Path: "third_party/egl/registry"},
+		"b": {Id: "b", Path: "third_party/opengl/registry"},
+		"c": {Id: "c", Path: "third_party/externals/c"},
+	}
+	collisions := basenameCollisions(entries)
+	require.Len(t, collisions, 1)
+	require.Len(t, collisions["registry"], 2)
+	assert.Equal(t, "a", collisions["registry"][0].Id)
+	assert.Equal(t, "b", collisions["registry"][1].Id)
+}
+
+func TestBasenameCollisions_CommittedEntries_OnlyBin(t *testing.T) {
+	collisions := BasenameCollisions()
+	require.Len(t, collisions, 1)
+	assert.Len(t, collisions["bin"], 2)
+}
+
+func TestSuspiciousSharedSHAs_CommittedEntries_None(t *testing.T) {
+	assert.Empty(t, SuspiciousSharedSHAs())
+	assert.Len(t, suspiciousSharedSHAs(deps, nil), 1, "buildbot and sk share a revision")
+}
+
+func TestSuspiciousSharedSHAs_CopyPaste_Flagged(t *testing.T) {
+	entries := deps_parser.DepsEntries{}
+	for id, e := range deps {
+		cp := *e
+		entries[id] = &cp
+	}
+	entries["dawn.googlesource.com/dawn"].Version = entries["chromium.googlesource.com/chromium/deps/icu"].Version
+
+	groups := suspiciousSharedSHAs(entries, SharedSHAAllowlist)
+	require.Len(t, groups, 1)
+	require.Len(t, groups[0], 2)
+	assert.Equal(t, "chromium.googlesource.com/chromium/deps/icu", groups[0][0].Id)
+	assert.Equal(t, "dawn.googlesource.com/dawn", groups[0][1].Id)
+}
+
+func TestSlugCollisions(t *testing.T) {
+	assert.Empty(t, SlugCollisions())
+
+	collisions := slugCollisions(deps_parser.DepsEntries{
+		"a": {Id: "a", Version: testHash, Path: "third_party/foo-bar"},
+		"b": {Id: "b", Version: testHash, Path: "third_party/foo_bar"},
+	})
+	require.Len(t, collisions["third-party-foo-bar"], 2)
+}
+
+func TestCaseInsensitivePathCollisions(t *testing.T) {
+	assert.Empty(t, CaseInsensitivePathCollisions())
+
+	groups := caseInsensitivePathCollisions(deps_parser.DepsEntries{
+		"a":   {Id: "a", Path: "Third_Party/X"},
+		"b":   {Id: "b", Path: "third_party/x"},
+		"c":   {Id: "c", Path: "third_party/y"},
+		"bin": {Id: "bin", Path: "bin"},
+		"sk":  {Id: "sk", Path: "bin"},
+	})
+	require.Len(t, groups, 1)
+	require.Len(t, groups[0], 2)
+	assert.Equal(t, "a", groups[0][0].Id)
+	assert.Equal(t, "b", groups[0][1].Id)
+}
+
+func TestCrossCaseRisk(t *testing.T) {
+	assert.Empty(t, CrossCaseRisk(deps))
+
+	entries := deps_parser.DepsEntries{
+		"example.googlesource.com/Foo":   {Id: "example.googlesource.com/Foo", Version: testHash, Path: "third_party/externals/Foo"},
+		"example.googlesource.com/bar":   {Id: "example.googlesource.com/bar", Version: testHash, Path: "third_party/externals/foo"},
+		"example.googlesource.com/Baz":   {Id: "example.googlesource.com/Baz", Version: testHash, Path: "third_party/baz-src"},
+		"example.googlesource.com/quux":  {Id: "example.googlesource.com/quux", Version: testHash, Path: "third_party/externals/baz"},
+		"example.googlesource.com/other": {Id: "example.googlesource.com/other", Version: testHash, Path: "third_party/externals/other"},
+	}
+	var ids []string
+	for _, e := range CrossCaseRisk(entries) {
+		ids = append(ids, e.Id)
+	}
+	// Foo and bar have Paths differing in case, and the Path of quux differs in
+	// case from the repo name of Baz.
+	assert.Equal(t, []string{
+		"example.googlesource.com/Foo",
+		"example.googlesource.com/bar",
+		"example.googlesource.com/quux",
+	}, ids)
+}
+
+func TestNearDuplicateIds(t *testing.T) {
+	assert.Empty(t, NearDuplicateIds(deps, 1))
+
+	entries := deps_parser.DepsEntries{
+		"github.com/harfbuzz/harfbuzz": {Id: "github.com/harfbuzz/harfbuzz"},
+		"github.com/harfbuzz/harfbzz":  {Id: "github.com/harfbuzz/harfbzz"},
+		"github.com/google/brotli":     {Id: "github.com/google/brotli"},
+	}
+	assert.Equal(t, [][2]string{
+		{"github.com/harfbuzz/harfbuzz", "github.com/harfbuzz/harfbzz"},
+	}, NearDuplicateIds(entries, 1))
+	assert.Empty(t, NearDuplicateIds(entries, 0))
+}
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein("abc", "abc"))
+	assert.Equal(t, 1, levenshtein("harfbzz", "harfbuzz"))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+	assert.Equal(t, 3, levenshtein("", "abc"))
+}
@@ -3,25 +3,172 @@
 // Use of this source code is governed by a BSD-style license that can be
 // found in the LICENSE file.
 
-//go:generate bazelisk run //:go -- run ./generate.go
 package deps
 
 import (
+	"path"
+	"sort"
+	"strings"
+
 	"go.skia.org/infra/go/depot_tools/deps_parser"
 	"go.skia.org/infra/go/skerr"
 )
 
+// NormalizeId normalizes the given dependency ID as deps_parser.NormalizeDep,
+// additionally tolerating surrounding whitespace, a leading URL scheme like
+// "https://" and a trailing ".git" or "/".
+func NormalizeId(dep string) string {
+	id := strings.TrimSpace(dep)
+	if _, rest, ok := strings.Cut(id, "://"); ok {
+		id = rest
+	}
+	id = strings.TrimSuffix(id, "/")
+	id = strings.TrimSuffix(id, ".git")
+	return deps_parser.NormalizeDep(id)
+}
+
 // Get retrieves the given dependency. Returns an error of the given dependency
 // does not exist.
 func Get(dep string) (*deps_parser.DepsEntry, error) {
-	entry := deps.Get(dep)
+	entry := deps.Get(NormalizeId(dep))
 	if entry == nil {
-		return nil, skerr.Fmt("unknown dependency %q (normalized as %q)", dep, deps_parser.NormalizeDep(dep))
+		return nil, skerr.Fmt("unknown dependency %q (normalized as %q)", dep, NormalizeId(dep))
 	}
 	// Return a copy to prevent modification of the package-local entries.
-	return &deps_parser.DepsEntry{
-		Id:      entry.Id,
-		Version: entry.Version,
-		Path:    entry.Path,
-	}, nil
+	cp := *entry
+	return &cp, nil
+}
+
+// GetMany retrieves the given dependencies, returning copies of those found,
+// keyed by the requested ID, and the requested IDs which were not found.
+func GetMany(ids ...string) (map[string]deps_parser.DepsEntry, []string) {
+	found := make(map[string]deps_parser.DepsEntry, len(ids))
+	var missing []string
+	for _, id := range ids {
+		if e := deps.Get(NormalizeId(id)); e != nil {
+			found[id] = *e
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	return found, missing
+}
+
+// Entries returns a copy of all of the dependencies.
+func Entries() deps_parser.DepsEntries {
+	rv := make(deps_parser.DepsEntries, len(deps))
+	for id, e := range deps {
+		cp := *e
+		rv[id] = &cp
+	}
+	return rv
+}
+
+// Walk calls fn for each dependency in order of ID, stopping early if fn
+// returns false.
+func Walk(fn func(deps_parser.DepsEntry) bool) {
+	ids := make([]string, 0, len(deps))
+	for id := range deps {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if !fn(*deps[id]) {
+			return
+		}
+	}
+}
+
+// GetAllByPath returns every dependency, sorted by ID, which is checked out at
+// the given path. Note that multiple CIPD packages may share a path, eg. "bin".
+func GetAllByPath(path string) []deps_parser.DepsEntry {
+	path = strings.TrimSuffix(path, "/")
+	var rv []deps_parser.DepsEntry
+	for _, e := range sortedEntries(deps) {
+		if e.Path == path {
+			rv = append(rv, e)
+		}
+	}
+	return rv
+}
+
+// OwnerOf returns the dependency whose Path is the longest prefix of the given
+// file path, relative to the root of the checkout. If several dependencies
+// share that Path, the first by ID is returned.
+func OwnerOf(filePath string) (deps_parser.DepsEntry, bool) {
+	filePath = path.Clean(filePath)
+	var owner deps_parser.DepsEntry
+	found := false
+	for _, e := range sortedEntries(deps) {
+		if filePath != e.Path && !strings.HasPrefix(filePath, e.Path+"/") {
+			continue
+		}
+		if !found || len(e.Path) > len(owner.Path) {
+			owner = e
+			found = true
+		}
+	}
+	return owner, found
+}
+
+// DefaultBranch is the branch which a dependency is assumed to track unless
+// listed in branches.
+const DefaultBranch = "main"
+
+// branches maps dependency IDs to the branch they track, for dependencies which
+// don't track DefaultBranch. DEPS does not record branches, so neither Generate
+// nor ParseDeps fills this in; it must be maintained by hand, and is currently
+// empty.
+var branches = map[string]string{}
+
+// Branch returns the name of the branch which the given entry tracks. This is
+// DefaultBranch unless the entry is listed in the hand-maintained branches
+// map, since DEPS itself does not record branches.
+func Branch(e deps_parser.DepsEntry) string {
+	if b, ok := branches[e.Id]; ok {
+		return b
+	}
+	return DefaultBranch
+}
+
+// ByBranch returns all dependencies which track the given branch, according to
+// Branch, sorted by ID. Until branches are listed, every dependency is reported
+// as tracking DefaultBranch.
+func ByBranch(name string) []deps_parser.DepsEntry {
+	var rv []deps_parser.DepsEntry
+	for _, e := range sortedEntries(deps) {
+		if Branch(e) == name {
+			rv = append(rv, e)
+		}
+	}
+	return rv
+}
+
+// requires maps dependency IDs to the IDs of other dependencies which they
+// need, as documented in DEPS.
+var requires = map[string][]string{
+	// Dawn requires jinja2 and markupsafe for the code generator and abseil
+	// for string formatting.
+	"dawn.googlesource.com/dawn": {
+		"chromium.googlesource.com/chromium/src/third_party/jinja2",
+		"chromium.googlesource.com/chromium/src/third_party/markupsafe",
+		"skia.googlesource.com/external/github.com/abseil/abseil-cpp",
+	},
+}
+
+// Requires returns the IDs of the dependencies which the given entry needs.
+func Requires(e deps_parser.DepsEntry) []string {
+	return requires[e.Id]
+}
+
+// sortedEntries returns copies of the given entries, sorted by ID.
+func sortedEntries(entries deps_parser.DepsEntries) []deps_parser.DepsEntry {
+	rv := make([]deps_parser.DepsEntry, 0, len(entries))
+	for _, e := range entries {
+		rv = append(rv, *e)
+	}
+	sort.Slice(rv, func(i, j int) bool {
+		return rv[i].Id < rv[j].Id
+	})
+	return rv
 }
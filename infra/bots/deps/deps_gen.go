@@ -1,5 +1,7 @@
 // Code generated by "go run generate.go"; DO NOT EDIT
 
+//go:generate bazelisk run //:go -- run ./generate.go
+
 package deps
 
 import (
@@ -11,250 +13,300 @@ var deps = deps_parser.DepsEntries{
 		Id:      "android.googlesource.com/platform/external/dng_sdk",
 		Version: "c8d0c9b1d16bfda56f15165d39e0ffa360a11123",
 		Path:    "third_party/externals/dng_sdk",
+		Type:    deps_parser.DepType_Git,
 	},
 	"android.googlesource.com/platform/external/libmicrohttpd": {
 		Id:      "android.googlesource.com/platform/external/libmicrohttpd",
 		Version: "748945ec6f1c67b7efc934ab0808e1d32f2fb98d",
 		Path:    "third_party/externals/microhttpd",
+		Type:    deps_parser.DepType_Git,
 	},
 	"android.googlesource.com/platform/external/perfetto": {
 		Id:      "android.googlesource.com/platform/external/perfetto",
 		Version: "93885509be1c9240bc55fa515ceb34811e54a394",
 		Path:    "third_party/externals/perfetto",
+		Type:    deps_parser.DepType_Git,
 	},
 	"android.googlesource.com/platform/external/piex": {
 		Id:      "android.googlesource.com/platform/external/piex",
 		Version: "bb217acdca1cc0c16b704669dd6f91a1b509c406",
 		Path:    "third_party/externals/piex",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/angle/angle": {
 		Id:      "chromium.googlesource.com/angle/angle",
 		Version: "f5196a27b9b6bdc358191717f2b5a3ba824d1e82",
 		Path:    "third_party/externals/angle2",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/chromium/deps/icu": {
 		Id:      "chromium.googlesource.com/chromium/deps/icu",
 		Version: "364118a1d9da24bb5b770ac3d762ac144d6da5a4",
 		Path:    "third_party/externals/icu",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/chromium/deps/libjpeg_turbo": {
 		Id:      "chromium.googlesource.com/chromium/deps/libjpeg_turbo",
 		Version: "ccfbe1c82a3b6dbe8647ceb36a3f9ee711fba3cf",
 		Path:    "third_party/externals/libjpeg-turbo",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/chromium/src/base/allocator/partition_allocator": {
 		Id:      "chromium.googlesource.com/chromium/src/base/allocator/partition_allocator",
 		Version: "ce13777cb731e0a60c606d1741091fd11a0574d7",
 		Path:    "third_party/externals/partition_alloc",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/chromium/src/buildtools": {
 		Id:      "chromium.googlesource.com/chromium/src/buildtools",
 		Version: "1760ff6d7267dd97ae1968c7bee9ce04a2a8489d",
 		Path:    "buildtools",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/chromium/src/third_party/freetype2": {
 		Id:      "chromium.googlesource.com/chromium/src/third_party/freetype2",
 		Version: "83af801b552111e37d9466a887e1783a0fb5f196",
 		Path:    "third_party/externals/freetype",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/chromium/src/third_party/jinja2": {
 		Id:      "chromium.googlesource.com/chromium/src/third_party/jinja2",
 		Version: "e2d024354e11cc6b041b0cff032d73f0c7e43a07",
 		Path:    "third_party/externals/jinja2",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/chromium/src/third_party/markupsafe": {
 		Id:      "chromium.googlesource.com/chromium/src/third_party/markupsafe",
 		Version: "0bad08bb207bbfc1d6f3bbc82b9242b0c50e5794",
 		Path:    "third_party/externals/markupsafe",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/chromium/src/third_party/zlib": {
 		Id:      "chromium.googlesource.com/chromium/src/third_party/zlib",
 		Version: "646b7f569718921d7d4b5b8e22572ff6c76f2596",
 		Path:    "third_party/externals/zlib",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/codecs/libgav1": {
 		Id:      "chromium.googlesource.com/codecs/libgav1",
 		Version: "5cf722e659014ebaf2f573a6dd935116d36eadf1",
 		Path:    "third_party/externals/libgav1",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/external/github.com/GPUOpen-LibrariesAndSDKs/VulkanMemoryAllocator": {
 		Id:      "chromium.googlesource.com/external/github.com/GPUOpen-LibrariesAndSDKs/VulkanMemoryAllocator",
 		Version: "a6bfc237255a6bac1513f7c1ebde6d8aed6b5191",
 		Path:    "third_party/externals/vulkanmemoryallocator",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/external/github.com/KhronosGroup/SPIRV-Cross": {
 		Id:      "chromium.googlesource.com/external/github.com/KhronosGroup/SPIRV-Cross",
 		Version: "b8fcf307f1f347089e3c46eb4451d27f32ebc8d3",
 		Path:    "third_party/externals/spirv-cross",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/external/github.com/KhronosGroup/Vulkan-Headers": {
 		Id:      "chromium.googlesource.com/external/github.com/KhronosGroup/Vulkan-Headers",
 		Version: "6a74a7d65cafa19e38ec116651436cce6efd5b2e",
 		Path:    "third_party/externals/vulkan-headers",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/external/github.com/KhronosGroup/Vulkan-Tools": {
 		Id:      "chromium.googlesource.com/external/github.com/KhronosGroup/Vulkan-Tools",
 		Version: "2744de9936755fea6912d47e7a0a8857d8a4fdee",
 		Path:    "third_party/externals/vulkan-tools",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/external/github.com/KhronosGroup/Vulkan-Utility-Libraries": {
 		Id:      "chromium.googlesource.com/external/github.com/KhronosGroup/Vulkan-Utility-Libraries",
 		Version: "5a72ae0208f1bf116af74ef31cc6f6c7ff4acec6",
 		Path:    "third_party/externals/vulkan-utility-libraries",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/external/github.com/google/highway": {
 		Id:      "chromium.googlesource.com/external/github.com/google/highway",
 		Version: "424360251cdcfc314cfc528f53c872ecd63af0f0",
 		Path:    "third_party/externals/highway",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/external/github.com/google/oboe": {
 		Id:      "chromium.googlesource.com/external/github.com/google/oboe",
 		Version: "b02a12d1dd821118763debec6b83d00a8a0ee419",
 		Path:    "third_party/externals/oboe",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/external/github.com/harfbuzz/harfbuzz": {
 		Id:      "chromium.googlesource.com/external/github.com/harfbuzz/harfbuzz",
 		Version: "a070f9ebbe88dc71b248af9731dd49ec93f4e6e6",
 		Path:    "third_party/externals/harfbuzz",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/external/github.com/libexpat/libexpat": {
 		Id:      "chromium.googlesource.com/external/github.com/libexpat/libexpat",
 		Version: "624da0f593bb8d7e146b9f42b06d8e6c80d032a3",
 		Path:    "third_party/externals/expat",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/external/github.com/unicode-org/icu4x": {
 		Id:      "chromium.googlesource.com/external/github.com/unicode-org/icu4x",
 		Version: "bcf4f7198d4dc5f3127e84a6ca657c88e7d07a13",
 		Path:    "third_party/externals/icu4x",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/external/github.com/unicode-org/unicodetools": {
 		Id:      "chromium.googlesource.com/external/github.com/unicode-org/unicodetools",
 		Version: "66a3fa9dbdca3b67053a483d130564eabc5fe095",
 		Path:    "third_party/externals/unicodetools",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/external/gitlab.com/wg1/jpeg-xl": {
 		Id:      "chromium.googlesource.com/external/gitlab.com/wg1/jpeg-xl",
 		Version: "a205468bc5d3a353fb15dae2398a101dff52f2d3",
 		Path:    "third_party/externals/libjxl",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/libyuv/libyuv": {
 		Id:      "chromium.googlesource.com/libyuv/libyuv",
 		Version: "d248929c059ff7629a85333699717d7a677d8d96",
 		Path:    "third_party/externals/libyuv",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/vulkan-deps": {
 		Id:      "chromium.googlesource.com/vulkan-deps",
 		Version: "61b3802219e0a29b70b63d17dbd236620b85db22",
 		Path:    "third_party/externals/vulkan-deps",
+		Type:    deps_parser.DepType_Git,
 	},
 	"chromium.googlesource.com/webm/libwebp": {
 		Id:      "chromium.googlesource.com/webm/libwebp",
 		Version: "845d5476a866141ba35ac133f856fa62f0b7445f",
 		Path:    "third_party/externals/libwebp",
+		Type:    deps_parser.DepType_Git,
 	},
 	"dawn.googlesource.com/dawn": {
 		Id:      "dawn.googlesource.com/dawn",
 		Version: "22a8762fea90d2d9fbfc592d2bf2a438b66f22f4",
 		Path:    "third_party/externals/dawn",
+		Type:    deps_parser.DepType_Git,
 	},
 	"github.com/skia-dev/delaunator-cpp": {
 		Id:      "github.com/skia-dev/delaunator-cpp",
 		Version: "98305ef6c4e862f7d48df9cc647b690d796fec68",
 		Path:    "third_party/externals/delaunator-cpp",
+		Type:    deps_parser.DepType_Git,
 	},
 	"infra/3pp/tools/ninja": {
 		Id:      "infra/3pp/tools/ninja",
 		Version: "version:2@1.12.1.chromium.4",
 		Path:    "bin",
+		Type:    deps_parser.DepType_Cipd,
 	},
 	"skia.googlesource.com/buildbot": {
 		Id:      "skia.googlesource.com/buildbot",
 		Version: "ca6066d7097cf6a175b48c03d5e9c24c1ee0262f",
 		Path:    "infra/skia-infra",
+		Type:    deps_parser.DepType_Git,
 	},
 	"skia.googlesource.com/external/github.com/AOMediaCodec/libavif": {
 		Id:      "skia.googlesource.com/external/github.com/AOMediaCodec/libavif",
 		Version: "55aab4ac0607ab651055d354d64c4615cf3d8000",
 		Path:    "third_party/externals/libavif",
+		Type:    deps_parser.DepType_Git,
 	},
 	"skia.googlesource.com/external/github.com/FRIGN/libgrapheme": {
 		Id:      "skia.googlesource.com/external/github.com/FRIGN/libgrapheme",
 		Version: "c0cab63c5300fa12284194fbef57aa2ed62a94c0",
 		Path:    "third_party/externals/libgrapheme",
+		Type:    deps_parser.DepType_Git,
 	},
 	"skia.googlesource.com/external/github.com/GPUOpen-LibrariesAndSDKs/D3D12MemoryAllocator": {
 		Id:      "skia.googlesource.com/external/github.com/GPUOpen-LibrariesAndSDKs/D3D12MemoryAllocator",
 		Version: "169895d529dfce00390a20e69c2f516066fe7a3b",
 		Path:    "third_party/externals/d3d12allocator",
+		Type:    deps_parser.DepType_Git,
 	},
 	"skia.googlesource.com/external/github.com/KhronosGroup/EGL-Registry": {
 		Id:      "skia.googlesource.com/external/github.com/KhronosGroup/EGL-Registry",
 		Version: "b055c9b483e70ecd57b3cf7204db21f5a06f9ffe",
 		Path:    "third_party/externals/egl-registry",
+		Type:    deps_parser.DepType_Git,
 	},
 	"skia.googlesource.com/external/github.com/KhronosGroup/OpenGL-Registry": {
 		Id:      "skia.googlesource.com/external/github.com/KhronosGroup/OpenGL-Registry",
 		Version: "14b80ebeab022b2c78f84a573f01028c96075553",
 		Path:    "third_party/externals/opengl-registry",
+		Type:    deps_parser.DepType_Git,
 	},
 	"skia.googlesource.com/external/github.com/KhronosGroup/SPIRV-Headers": {
 		Id:      "skia.googlesource.com/external/github.com/KhronosGroup/SPIRV-Headers",
 		Version: "3f17b2af6784bfa2c5aa5dbb8e0e74a607dd8b3b",
 		Path:    "third_party/externals/spirv-headers",
+		Type:    deps_parser.DepType_Git,
 	},
 	"skia.googlesource.com/external/github.com/KhronosGroup/SPIRV-Tools": {
 		Id:      "skia.googlesource.com/external/github.com/KhronosGroup/SPIRV-Tools",
 		Version: "4d2f0b40bfe290dea6c6904dafdf7fd8328ba346",
 		Path:    "third_party/externals/spirv-tools",
+		Type:    deps_parser.DepType_Git,
 	},
 	"skia.googlesource.com/external/github.com/abseil/abseil-cpp": {
 		Id:      "skia.googlesource.com/external/github.com/abseil/abseil-cpp",
 		Version: "65a55c2ba891f6d2492477707f4a2e327a0b40dc",
 		Path:    "third_party/externals/abseil-cpp",
+		Type:    deps_parser.DepType_Git,
 	},
 	"skia.googlesource.com/external/github.com/emscripten-core/emsdk": {
 		Id:      "skia.googlesource.com/external/github.com/emscripten-core/emsdk",
 		Version: "a896e3d066448b3530dbcaa48869fafefd738f57",
 		Path:    "third_party/externals/emsdk",
+		Type:    deps_parser.DepType_Git,
 	},
 	"skia.googlesource.com/external/github.com/google/brotli": {
 		Id:      "skia.googlesource.com/external/github.com/google/brotli",
 		Version: "6d03dfbedda1615c4cba1211f8d81735575209c8",
 		Path:    "third_party/externals/brotli",
+		Type:    deps_parser.DepType_Git,
 	},
 	"skia.googlesource.com/external/github.com/google/wuffs-mirror-release-c": {
 		Id:      "skia.googlesource.com/external/github.com/google/wuffs-mirror-release-c",
 		Version: "e3f919ccfe3ef542cfc983a82146070258fb57f8",
 		Path:    "third_party/externals/wuffs",
+		Type:    deps_parser.DepType_Git,
 	},
 	"skia.googlesource.com/external/github.com/linebender/vello": {
 		Id:      "skia.googlesource.com/external/github.com/linebender/vello",
 		Version: "3ee3bea02164c5a816fe6c16ef4e3a810edb7620",
 		Path:    "third_party/externals/vello",
+		Type:    deps_parser.DepType_Git,
 	},
 	"skia.googlesource.com/external/github.com/ocornut/imgui": {
 		Id:      "skia.googlesource.com/external/github.com/ocornut/imgui",
 		Version: "55d35d8387c15bf0cfd71861df67af8cfbda7456",
 		Path:    "third_party/externals/imgui",
+		Type:    deps_parser.DepType_Git,
 	},
 	"skia.googlesource.com/third_party/libpng": {
 		Id:      "skia.googlesource.com/third_party/libpng",
 		Version: "ed217e3e601d8e462f7fd1e04bed43ac42212429",
 		Path:    "third_party/externals/libpng",
+		Type:    deps_parser.DepType_Git,
 	},
 	"skia/tools/bazel_build": {
 		Id:      "skia/tools/bazel_build",
 		Version: "git_revision:b5d31abb7bc772a69f800de45783768768437675",
 		Path:    "task_drivers",
+		Type:    deps_parser.DepType_Cipd,
 	},
 	"skia/tools/sk": {
 		Id:      "skia/tools/sk",
 		Version: "git_revision:ca6066d7097cf6a175b48c03d5e9c24c1ee0262f",
 		Path:    "bin",
+		Type:    deps_parser.DepType_Cipd,
 	},
 	"swiftshader.googlesource.com/SwiftShader": {
 		Id:      "swiftshader.googlesource.com/SwiftShader",
 		Version: "d91e98d1aa3f18398fd6bfadbb45060cd6e0db3b",
 		Path:    "third_party/externals/swiftshader",
+		Type:    deps_parser.DepType_Git,
 	},
 }
@@ -0,0 +1,167 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"bufio"
+	"bytes"
+	"go/build"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+	"go.skia.org/skia/infra/bots/deps/gen"
+)
+
+func TestDepsGen_ParseGeneratedSource_MatchesEntries(t *testing.T) {
+	src, err := os.ReadFile("deps_gen.go")
+	require.NoError(t, err)
+	entries, err := gen.ParseGeneratedSource(src)
+	require.NoError(t, err)
+	assert.Equal(t, deps, entries)
+}
+
+func TestGoGenerate_DepsGenOptions_ExactlyOneDirective(t *testing.T) {
+	pkg, err := build.ImportDir(".", 0)
+	require.NoError(t, err)
+	sources := map[string][]byte{}
+	for _, name := range pkg.GoFiles {
+		if name == "deps_gen.go" {
+			continue
+		}
+		src, err := os.ReadFile(name)
+		require.NoError(t, err)
+		sources[name] = src
+	}
+	var buf bytes.Buffer
+	require.NoError(t, gen.Generate(&buf, deps, gen.DepsGenOptions))
+	sources["deps_gen.go"] = buf.Bytes()
+
+	var directives []string
+	for name, src := range sources {
+		scanner := bufio.NewScanner(bytes.NewReader(src))
+		for scanner.Scan() {
+			if strings.HasPrefix(scanner.Text(), "//go:generate ") {
+				directives = append(directives, name+": "+scanner.Text())
+			}
+		}
+		require.NoError(t, scanner.Err())
+	}
+	assert.Equal(t, []string{"deps_gen.go: //go:generate bazelisk run //:go -- run ./generate.go"}, directives)
+}
+
+func TestGet_URLForm_Normalized(t *testing.T) {
+	const icu = "chromium.googlesource.com/chromium/deps/icu"
+	for _, dep := range []string{
+		icu,
+		"https://chromium.googlesource.com/chromium/deps/icu.git",
+		"https://chromium.googlesource.com/chromium/deps/icu/",
+		" chromium.googlesource.com/chromium/deps/icu.git ",
+	} {
+		assert.Equal(t, icu, NormalizeId(dep), dep)
+		e, err := Get(dep)
+		require.NoError(t, err, dep)
+		assert.Equal(t, icu, e.Id)
+	}
+
+	_, err := Get("https://example.com/nonexistent.git")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `(normalized as "example.com/nonexistent")`)
+}
+
+func TestGetMany_KnownAndUnknown(t *testing.T) {
+	found, missing := GetMany(
+		"chromium.googlesource.com/chromium/deps/icu",
+		"example.com/unknown",
+		"https://dawn.googlesource.com/dawn.git",
+		"infra/3pp/tools/ninja",
+		"bogus",
+	)
+	assert.Equal(t, []string{"example.com/unknown", "bogus"}, missing)
+	require.Len(t, found, 3)
+	assert.Equal(t, *deps["chromium.googlesource.com/chromium/deps/icu"], found["chromium.googlesource.com/chromium/deps/icu"])
+	assert.Equal(t, "dawn.googlesource.com/dawn", found["https://dawn.googlesource.com/dawn.git"].Id)
+	assert.Equal(t, "bin", found["infra/3pp/tools/ninja"].Path)
+}
+
+func TestBranch_NotListed_DefaultsToMain(t *testing.T) {
+	icu, err := Get("chromium.googlesource.com/chromium/deps/icu")
+	require.NoError(t, err)
+	assert.Equal(t, "main", Branch(*icu))
+	assert.Len(t, ByBranch("main"), len(deps))
+}
+
+func TestBranch_Listed_ReturnsExplicitBranch(t *testing.T) {
+	const id = "chromium.googlesource.com/chromium/deps/icu"
+	branches[id] = "chromium/6099"
+	defer delete(branches, id)
+
+	icu, err := Get(id)
+	require.NoError(t, err)
+	assert.Equal(t, "chromium/6099", Branch(*icu))
+	stable := ByBranch("chromium/6099")
+	require.Len(t, stable, 1)
+	assert.Equal(t, id, stable[0].Id)
+	assert.Len(t, ByBranch("main"), len(deps)-1)
+}
+
+func TestGetAllByPath(t *testing.T) {
+	bin := GetAllByPath("bin")
+	require.Len(t, bin, 2)
+	assert.Equal(t, "infra/3pp/tools/ninja", bin[0].Id)
+	assert.Equal(t, "skia/tools/sk", bin[1].Id)
+
+	icu := GetAllByPath("third_party/externals/icu/")
+	require.Len(t, icu, 1)
+	assert.Equal(t, "chromium.googlesource.com/chromium/deps/icu", icu[0].Id)
+
+	assert.Empty(t, GetAllByPath("third_party/externals/nonexistent"))
+}
+
+func TestEntries_ReturnsCopy(t *testing.T) {
+	entries := Entries()
+	require.Equal(t, deps, entries)
+	entries["infra/3pp/tools/ninja"].Version = "version:2@0.0.0"
+	assert.NotEqual(t, "version:2@0.0.0", deps["infra/3pp/tools/ninja"].Version)
+}
+
+func TestWalk_ReturnFalse_StopsEarly(t *testing.T) {
+	var visited []string
+	Walk(func(e deps_parser.DepsEntry) bool {
+		visited = append(visited, e.Id)
+		return len(visited) < 3
+	})
+	require.Len(t, visited, 3)
+	sorted := sortedEntries(deps)
+	for i, id := range visited {
+		assert.Equal(t, sorted[i].Id, id)
+	}
+
+	count := 0
+	Walk(func(deps_parser.DepsEntry) bool {
+		count++
+		return true
+	})
+	assert.Equal(t, len(deps), count)
+}
+
+func TestOwnerOf(t *testing.T) {
+	owner, ok := OwnerOf("third_party/externals/harfbuzz/src/x.c")
+	require.True(t, ok)
+	assert.Equal(t, "chromium.googlesource.com/external/github.com/harfbuzz/harfbuzz", owner.Id)
+
+	owner, ok = OwnerOf("./third_party/externals/icu")
+	require.True(t, ok)
+	assert.Equal(t, "chromium.googlesource.com/chromium/deps/icu", owner.Id)
+
+	_, ok = OwnerOf("third_party/externals/icu4x-not-really/x.c")
+	assert.False(t, ok)
+	_, ok = OwnerOf("src/core/SkCanvas.cpp")
+	assert.False(t, ok)
+}
@@ -0,0 +1,254 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+	"go.skia.org/skia/infra/bots/deps/gen"
+)
+
+// ChangedEntry describes a dependency which is present in both sets of entries
+// passed to Diff but differs between them.
+type ChangedEntry struct {
+	Old deps_parser.DepsEntry
+	New deps_parser.DepsEntry
+	// Commits is the number of commits between the old and new versions, or
+	// zero if unknown. It is not set by Diff.
+	Commits int
+}
+
+// RollMessage returns a one-line description of the change suitable for a CL
+// description, eg. "Roll harfbuzz from a070f9e to 0123456 (5 commits)". Git
+// hashes are abbreviated and the commit count is omitted if unknown.
+func (c ChangedEntry) RollMessage() string {
+	msg := fmt.Sprintf("Roll %s from %s to %s", shortName(c.New), abbrevVersion(c.Old.Version), abbrevVersion(c.New.Version))
+	switch {
+	case c.Commits == 1:
+		msg += " (1 commit)"
+	case c.Commits > 1:
+		msg += fmt.Sprintf(" (%d commits)", c.Commits)
+	}
+	return msg
+}
+
+// abbrevVersion shortens git hashes to minAbbrevHashLen characters, leaving
+// other versions untouched.
+func abbrevVersion(version string) string {
+	if ClassifyVersion(version) == VersionGit {
+		return version[:minAbbrevHashLen]
+	}
+	return version
+}
+
+// DepsDiff describes the differences between two sets of entries, keyed by ID.
+// Each slice is sorted by ID.
+type DepsDiff struct {
+	Added   []deps_parser.DepsEntry
+	Removed []deps_parser.DepsEntry
+	Changed []ChangedEntry
+}
+
+// DiffOptions control the behavior of DiffWithOptions.
+type DiffOptions struct {
+	// IgnoreTooling excludes entries which are IsTooling from the diff, eg.
+	// when comparing against upstream Skia, whose tooling versions differ
+	// legitimately.
+	IgnoreTooling bool
+}
+
+// Diff compares the given sets of entries by ID.
+func Diff(old, new deps_parser.DepsEntries) DepsDiff {
+	return DiffWithOptions(old, new, DiffOptions{})
+}
+
+// DiffWithOptions compares the given sets of entries by ID, as Diff, using the
+// given options.
+func DiffWithOptions(old, new deps_parser.DepsEntries, opts DiffOptions) DepsDiff {
+	var rv DepsDiff
+	for _, e := range sortedEntries(new) {
+		if opts.IgnoreTooling && IsTooling(e) {
+			continue
+		}
+		prev, ok := old[e.Id]
+		if !ok {
+			rv.Added = append(rv.Added, e)
+		} else if *prev != e {
+			rv.Changed = append(rv.Changed, ChangedEntry{Old: *prev, New: e})
+		}
+	}
+	for _, e := range sortedEntries(old) {
+		if opts.IgnoreTooling && IsTooling(e) {
+			continue
+		}
+		if _, ok := new[e.Id]; !ok {
+			rv.Removed = append(rv.Removed, e)
+		}
+	}
+	return rv
+}
+
+// FetchPlan describes the work needed to sync a checkout from one set of
+// entries to another.
+type FetchPlan struct {
+	// Fetch are the entries, sorted by ID, which must be fetched, ie. those
+	// which were added or changed.
+	Fetch []deps_parser.DepsEntry
+	// Remove are the entries, sorted by ID, whose Paths must be removed, ie.
+	// those which were removed or moved elsewhere.
+	Remove []deps_parser.DepsEntry
+	// FetchCount and RemoveCount are the lengths of Fetch and Remove.
+	FetchCount  int
+	RemoveCount int
+}
+
+// FetchDelta returns the FetchPlan for syncing from old to new.
+func FetchDelta(old, new deps_parser.DepsEntries) FetchPlan {
+	d := Diff(old, new)
+	var rv FetchPlan
+	rv.Fetch = append(rv.Fetch, d.Added...)
+	rv.Remove = append(rv.Remove, d.Removed...)
+	for _, c := range d.Changed {
+		rv.Fetch = append(rv.Fetch, c.New)
+		if c.Old.Path != c.New.Path {
+			rv.Remove = append(rv.Remove, c.Old)
+		}
+	}
+	sort.Slice(rv.Fetch, func(i, j int) bool {
+		return rv.Fetch[i].Id < rv.Fetch[j].Id
+	})
+	sort.Slice(rv.Remove, func(i, j int) bool {
+		return rv.Remove[i].Id < rv.Remove[j].Id
+	})
+	rv.FetchCount, rv.RemoveCount = len(rv.Fetch), len(rv.Remove)
+	return rv
+}
+
+// DiffStats summarizes a DepsDiff.
+type DiffStats struct {
+	Added   int
+	Removed int
+	Changed int
+	// Delta is the change in the number of entries, ie. Added - Removed.
+	Delta int
+}
+
+// Stats returns the number of added, removed and changed entries.
+func (d DepsDiff) Stats() DiffStats {
+	return DiffStats{
+		Added:   len(d.Added),
+		Removed: len(d.Removed),
+		Changed: len(d.Changed),
+		Delta:   len(d.Added) - len(d.Removed),
+	}
+}
+
+// CountDelta returns the change in the number of entries from old to new.
+func CountDelta(old, new deps_parser.DepsEntries) int {
+	return len(new) - len(old)
+}
+
+// TopChanged returns at most n changed entries, or none if n is not positive.
+// If ages is nil, the entries are ordered by ID. Otherwise, ages maps IDs to
+// the time of their change and the most recent changes come first, followed by
+// any entries missing from ages.
+func (d DepsDiff) TopChanged(n int, ages map[string]time.Time) []ChangedEntry {
+	rv := append([]ChangedEntry(nil), d.Changed...)
+	if ages != nil {
+		sort.SliceStable(rv, func(i, j int) bool {
+			ti, okI := ages[rv[i].New.Id]
+			tj, okJ := ages[rv[j].New.Id]
+			if okI != okJ {
+				return okI
+			}
+			return ti.After(tj)
+		})
+	}
+	if n < 0 {
+		n = 0
+	}
+	if len(rv) > n {
+		rv = rv[:n]
+	}
+	return rv
+}
+
+// PureVersionBumps returns the changed entries whose Version changed but whose
+// Path did not.
+func (d DepsDiff) PureVersionBumps() []ChangedEntry {
+	return d.filterChanged(func(c ChangedEntry) bool {
+		return c.Old.Version != c.New.Version && c.Old.Path == c.New.Path
+	})
+}
+
+// PureRelocations returns the changed entries whose Path changed but whose
+// Version did not.
+func (d DepsDiff) PureRelocations() []ChangedEntry {
+	return d.filterChanged(func(c ChangedEntry) bool {
+		return c.Old.Path != c.New.Path && c.Old.Version == c.New.Version
+	})
+}
+
+// ComplexChanges returns the changed entries whose Version and Path both
+// changed.
+func (d DepsDiff) ComplexChanges() []ChangedEntry {
+	return d.filterChanged(func(c ChangedEntry) bool {
+		return c.Old.Version != c.New.Version && c.Old.Path != c.New.Path
+	})
+}
+
+// filterChanged returns the changed entries for which the given function
+// returns true.
+func (d DepsDiff) filterChanged(keep func(ChangedEntry) bool) []ChangedEntry {
+	var rv []ChangedEntry
+	for _, c := range d.Changed {
+		if keep(c) {
+			rv = append(rv, c)
+		}
+	}
+	return rv
+}
+
+// SemanticallyEqual returns true if the given sets contain the same entries,
+// regardless of ordering or of the keys used in the maps. See
+// gen.SemanticallyEqual.
+func SemanticallyEqual(a, b deps_parser.DepsEntries) bool {
+	return gen.SemanticallyEqual(a, b)
+}
+
+// PathDiff compares only the Paths of the given sets of entries, returning the
+// sorted Paths which are present in new but not old and vice versa. Unlike a
+// diff keyed by ID, a dependency which moved to a new Path shows up as both an
+// added and a removed Path.
+func PathDiff(old, new deps_parser.DepsEntries) (added, removed []string) {
+	oldPaths := paths(old)
+	newPaths := paths(new)
+	for p := range newPaths {
+		if !oldPaths[p] {
+			added = append(added, p)
+		}
+	}
+	for p := range oldPaths {
+		if !newPaths[p] {
+			removed = append(removed, p)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// paths returns the set of Paths used by the given entries.
+func paths(entries deps_parser.DepsEntries) map[string]bool {
+	rv := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		rv[e.Path] = true
+	}
+	return rv
+}
@@ -0,0 +1,218 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+func TestPathDiff_Relocation_AddedAndRemoved(t *testing.T) {
+	old := deps_parser.DepsEntries{
+		"a": {Id: "a", Version: "1", Path: "third_party/externals/a"},
+		"b": {Id: "b", Version: "1", Path: "third_party/externals/b"},
+	}
+	new := deps_parser.DepsEntries{
+		"a": {Id: "a", Version: "2", Path: "third_party/externals/a"},
+		"b": {Id: "b", Version: "1", Path: "third_party/b"},
+	}
+	added, removed := PathDiff(old, new)
+	assert.Equal(t, []string{"third_party/b"}, added)
+	assert.Equal(t, []string{"third_party/externals/b"}, removed)
+}
+
+func TestPathDiff_SameSet_Empty(t *testing.T) {
+	added, removed := PathDiff(deps, deps)
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+}
+
+// diffTestEntries returns a pair of sets in which a, b and c have changed, d
+// was removed and e was added.
+func diffTestEntries() (deps_parser.DepsEntries, deps_parser.DepsEntries) {
+	old := deps_parser.DepsEntries{
+		"a": {Id: "a", Version: "1", Path: "a"},
+		"b": {Id: "b", Version: "1", Path: "b"},
+		"c": {Id: "c", Version: "1", Path: "c"},
+		"d": {Id: "d", Version: "1", Path: "d"},
+	}
+	new := deps_parser.DepsEntries{
+		"a": {Id: "a", Version: "2", Path: "a"},
+		"b": {Id: "b", Version: "2", Path: "b"},
+		"c": {Id: "c", Version: "2", Path: "c"},
+		"e": {Id: "e", Version: "1", Path: "e"},
+	}
+	return old, new
+}
+
+func changedIds(changed []ChangedEntry) []string {
+	var rv []string
+	for _, c := range changed {
+		rv = append(rv, c.New.Id)
+	}
+	return rv
+}
+
+func entryIds(entries []deps_parser.DepsEntry) []string {
+	var rv []string
+	for _, e := range entries {
+		rv = append(rv, e.Id)
+	}
+	return rv
+}
+
+func TestDiff(t *testing.T) {
+	d := Diff(diffTestEntries())
+	assert.Equal(t, []deps_parser.DepsEntry{{Id: "e", Version: "1", Path: "e"}}, d.Added)
+	assert.Equal(t, []deps_parser.DepsEntry{{Id: "d", Version: "1", Path: "d"}}, d.Removed)
+	assert.Equal(t, []string{"a", "b", "c"}, changedIds(d.Changed))
+	assert.Equal(t, "1", d.Changed[0].Old.Version)
+	assert.Equal(t, "2", d.Changed[0].New.Version)
+}
+
+func TestDepsDiff_Stats(t *testing.T) {
+	old, new := diffTestEntries()
+	new["f"] = &deps_parser.DepsEntry{Id: "f", Version: "1", Path: "f"}
+	assert.Equal(t, DiffStats{Added: 2, Removed: 1, Changed: 3, Delta: 1}, Diff(old, new).Stats())
+	assert.Equal(t, 1, CountDelta(old, new))
+	assert.Equal(t, -1, CountDelta(new, old))
+	assert.Equal(t, 0, CountDelta(diffTestEntries()))
+}
+
+func TestDepsDiff_TopChanged_NoAges_SortedById(t *testing.T) {
+	d := Diff(diffTestEntries())
+	assert.Equal(t, []string{"a", "b"}, changedIds(d.TopChanged(2, nil)))
+	assert.Equal(t, []string{"a", "b", "c"}, changedIds(d.TopChanged(10, nil)))
+}
+
+func TestDepsDiff_TopChanged_NonPositive_Empty(t *testing.T) {
+	d := Diff(diffTestEntries())
+	assert.Empty(t, d.TopChanged(0, nil))
+	assert.Empty(t, d.TopChanged(-1, nil))
+}
+
+func TestDepsDiff_TopChanged_Ages_MostRecentFirst(t *testing.T) {
+	d := Diff(diffTestEntries())
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	ages := map[string]time.Time{
+		"a": now.Add(-2 * time.Hour),
+		"c": now,
+	}
+	assert.Equal(t, []string{"c", "a"}, changedIds(d.TopChanged(2, ages)))
+	assert.Equal(t, []string{"c", "a", "b"}, changedIds(d.TopChanged(3, ages)))
+}
+
+// relocationTestDiff returns a diff in which "bump" only changed its Version,
+// "move" only changed its Path and "both" changed both.
+func relocationTestDiff() DepsDiff {
+	old := deps_parser.DepsEntries{
+		"bump": {Id: "bump", Version: "1", Path: "third_party/externals/bump"},
+		"move": {Id: "move", Version: "1", Path: "third_party/externals/move"},
+		"both": {Id: "both", Version: "1", Path: "third_party/externals/both"},
+		"same": {Id: "same", Version: "1", Path: "third_party/externals/same"},
+	}
+	new := deps_parser.DepsEntries{
+		"bump": {Id: "bump", Version: "2", Path: "third_party/externals/bump"},
+		"move": {Id: "move", Version: "1", Path: "third_party/move"},
+		"both": {Id: "both", Version: "2", Path: "third_party/both"},
+		"same": {Id: "same", Version: "1", Path: "third_party/externals/same"},
+	}
+	return Diff(old, new)
+}
+
+func TestDepsDiff_PureVersionBumps(t *testing.T) {
+	assert.Equal(t, []string{"bump"}, changedIds(relocationTestDiff().PureVersionBumps()))
+}
+
+func TestDepsDiff_ComplexChanges(t *testing.T) {
+	d := relocationTestDiff()
+	assert.Equal(t, []string{"both"}, changedIds(d.ComplexChanges()))
+	assert.Equal(t, []string{"both", "bump", "move"}, changedIds(d.Changed))
+}
+
+func TestDepsDiff_PureRelocations(t *testing.T) {
+	relocations := relocationTestDiff().PureRelocations()
+	assert.Equal(t, []string{"move"}, changedIds(relocations))
+	assert.Equal(t, "third_party/move", relocations[0].New.Path)
+}
+
+func TestChangedEntry_RollMessage(t *testing.T) {
+	const harfbuzz = "chromium.googlesource.com/external/github.com/harfbuzz/harfbuzz"
+	old := *deps[harfbuzz]
+	new := old
+	new.Version = "0123456789abcdef0123456789abcdef01234567"
+
+	c := ChangedEntry{Old: old, New: new}
+	assert.Equal(t, "Roll harfbuzz from a070f9e to 0123456", c.RollMessage())
+
+	c.Commits = 5
+	assert.Equal(t, "Roll harfbuzz from a070f9e to 0123456 (5 commits)", c.RollMessage())
+
+	c.Commits = 1
+	assert.Equal(t, "Roll harfbuzz from a070f9e to 0123456 (1 commit)", c.RollMessage())
+}
+
+func TestChangedEntry_RollMessage_CIPD(t *testing.T) {
+	old := *deps["infra/3pp/tools/ninja"]
+	new := old
+	new.Version = "version:2@1.12.2"
+	c := ChangedEntry{Old: old, New: new}
+	assert.Equal(t, "Roll ninja from version:2@1.12.1.chromium.4 to version:2@1.12.2", c.RollMessage())
+}
+
+func TestDiffWithOptions_IgnoreTooling(t *testing.T) {
+	new := Entries()
+	new["infra/3pp/tools/ninja"].Version = "version:2@1.12.2"
+	delete(new, "skia/tools/sk")
+
+	d := DiffWithOptions(deps, new, DiffOptions{IgnoreTooling: true})
+	assert.Empty(t, d.Added)
+	assert.Empty(t, d.Removed)
+	assert.Empty(t, d.Changed)
+
+	d = DiffWithOptions(deps, new, DiffOptions{})
+	assert.Equal(t, []string{"infra/3pp/tools/ninja"}, changedIds(d.Changed))
+	assert.Len(t, d.Removed, 1)
+	assert.Equal(t, Diff(deps, new), d)
+
+	new[vulkanHeaders].Version = newHash
+	d = DiffWithOptions(deps, new, DiffOptions{IgnoreTooling: true})
+	assert.Equal(t, []string{vulkanHeaders}, changedIds(d.Changed))
+}
+
+func TestFetchDelta_SingleBump(t *testing.T) {
+	new := Entries()
+	new[vulkanHeaders].Version = newHash
+	plan := FetchDelta(deps, new)
+	assert.Equal(t, 1, plan.FetchCount)
+	assert.Equal(t, 0, plan.RemoveCount)
+	require.Len(t, plan.Fetch, 1)
+	assert.Equal(t, newHash, plan.Fetch[0].Version)
+	assert.Empty(t, plan.Remove)
+}
+
+func TestFetchDelta_RelocationsRemoveOldPath(t *testing.T) {
+	old := deps_parser.DepsEntries{
+		"bump": {Id: "bump", Version: "1", Path: "third_party/externals/bump"},
+		"move": {Id: "move", Version: "1", Path: "third_party/externals/move"},
+		"gone": {Id: "gone", Version: "1", Path: "third_party/externals/gone"},
+	}
+	new := deps_parser.DepsEntries{
+		"bump": {Id: "bump", Version: "2", Path: "third_party/externals/bump"},
+		"move": {Id: "move", Version: "1", Path: "third_party/move"},
+		"add":  {Id: "add", Version: "1", Path: "third_party/externals/add"},
+	}
+	plan := FetchDelta(old, new)
+	assert.Equal(t, []string{"add", "bump", "move"}, entryIds(plan.Fetch))
+	assert.Equal(t, []string{"gone", "move"}, entryIds(plan.Remove))
+	assert.Equal(t, "third_party/externals/move", plan.Remove[1].Path)
+	assert.Equal(t, 3, plan.FetchCount)
+	assert.Equal(t, 2, plan.RemoveCount)
+}
@@ -0,0 +1,191 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+	"go.skia.org/infra/go/skerr"
+)
+
+// shortName returns a short identifier for the given entry, suitable for use
+// in variable names, eg. "libjpeg_turbo". Git dependencies are named after the
+// basename of their Path and CIPD packages after the basename of their ID,
+// since several packages may share a Path.
+func shortName(e deps_parser.DepsEntry) string {
+	base := path.Base(e.Path)
+	if ClassifyVersion(e.Version) == VersionCIPD {
+		base = path.Base(e.Id)
+	}
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(base))
+}
+
+// WriteGNArgs writes the given entries, sorted by ID, as GN args of the form
+// `skia_<shortname>_rev = "<version>"`. CIPD packages are commented out, since
+// they are not synced as git repositories.
+func WriteGNArgs(w io.Writer, entries deps_parser.DepsEntries) error {
+	for _, e := range sortedEntries(entries) {
+		prefix := ""
+		if ClassifyVersion(e.Version) == VersionCIPD {
+			prefix = "# "
+		}
+		if _, err := fmt.Fprintf(w, "%sskia_%s_rev = %q\n", prefix, shortName(e), e.Version); err != nil {
+			return skerr.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// WriteYAML writes the given entries, sorted by ID, as a YAML sequence of
+// mappings. The encoding is done by hand to avoid a dependency on a YAML
+// library; every string is double-quoted, which YAML parses the same way as a
+// Go-quoted string for the characters found in DEPS.
+func WriteYAML(w io.Writer, entries deps_parser.DepsEntries) error {
+	for _, e := range sortedEntries(entries) {
+		if _, err := fmt.Fprintf(w, "- id: %q\n  path: %q\n  version: %q\n  scheme: %s\n", e.Id, e.Path, e.Version, ClassifyVersion(e.Version)); err != nil {
+			return skerr.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// shellVarName returns the name of the shell variable used for the given entry
+// by WriteShellVars.
+func shellVarName(e deps_parser.DepsEntry) string {
+	return "SKIA_DEP_" + strings.ToUpper(shortName(e))
+}
+
+// WriteShellVars writes the given entries, sorted by ID, as shell variable
+// assignments of the form SKIA_DEP_<NAME>='<version>', suitable for sourcing
+// from a shell script.
+func WriteShellVars(w io.Writer, entries deps_parser.DepsEntries) error {
+	for _, e := range sortedEntries(entries) {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", shellVarName(e), shellQuote(e.Version)); err != nil {
+			return skerr.Wrap(err)
+		}
+	}
+	return nil
+}
+
+var starlarkIdentRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// WriteStarlark writes the given entries as a Starlark dict assigned to
+// varName, mapping each ID to a dict of its path and version, sorted by ID,
+// suitable for loading from a .bzl file. Strings are Go-quoted, which Starlark
+// parses the same way for the characters found in DEPS.
+func WriteStarlark(w io.Writer, entries deps_parser.DepsEntries, varName string) error {
+	if !starlarkIdentRegex.MatchString(varName) {
+		return skerr.Fmt("invalid Starlark variable name %q", varName)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s = {\n", varName)
+	for _, e := range sortedEntries(entries) {
+		fmt.Fprintf(&b, "    %q: {\"path\": %q, \"version\": %q},\n", e.Id, e.Path, e.Version)
+	}
+	b.WriteString("}\n")
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return skerr.Wrap(err)
+	}
+	return nil
+}
+
+// shellQuote quotes the given string for use as a single shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// WriteSubmoduleScript writes a shell script which adds each of the given git
+// dependencies as a git submodule at its Path and checks out its pinned
+// version, in SyncOrder, for forks which use submodules rather than gclient.
+// CIPD packages cannot be submodules and are noted in comments instead.
+func WriteSubmoduleScript(w io.Writer, entries deps_parser.DepsEntries) error {
+	for _, e := range SyncOrder(entries) {
+		var err error
+		if ClassifyVersion(e.Version) == VersionGit {
+			_, err = fmt.Fprintf(w, "git submodule add %s %s\ngit -C %s checkout %s\n", shellQuote(RepoURL(e)), shellQuote(e.Path), shellQuote(e.Path), e.Version)
+		} else {
+			_, err = fmt.Fprintf(w, "# Skipping CIPD package %s at %s\n", e.Id, e.Path)
+		}
+		if err != nil {
+			return skerr.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// WritePinnedList writes an "id version" line for each of the given entries,
+// sorted by ID, with full, unabbreviated versions and LF line endings, for
+// reproducibility audits.
+func WritePinnedList(w io.Writer, entries deps_parser.DepsEntries) error {
+	for _, e := range sortedEntries(entries) {
+		if _, err := fmt.Fprintf(w, "%s %s\n", e.Id, e.Version); err != nil {
+			return skerr.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// WriteManifest writes a "path\tversion" line for each of the given entries,
+// sorted by Path and then by ID, suitable for feeding to an archiver.
+func WriteManifest(w io.Writer, entries deps_parser.DepsEntries) error {
+	sorted := sortedEntries(entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Path < sorted[j].Path
+	})
+	for _, e := range sorted {
+		if _, err := fmt.Fprintf(w, "%s\t%s\n", e.Path, e.Version); err != nil {
+			return skerr.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// WriteLockDelta writes a minimal patch describing the changes from old to new,
+// sorted by ID: "- id@version" for each removed entry, "+ id@version" for each
+// added entry, and both for each changed entry. Changed entries whose Path
+// moved also have their Paths appended, eg. "- id@version path".
+func WriteLockDelta(w io.Writer, old, new deps_parser.DepsEntries) error {
+	d := Diff(old, new)
+	lines := map[string][]string{}
+	for _, e := range d.Removed {
+		lines[e.Id] = []string{"- " + e.Id + "@" + e.Version}
+	}
+	for _, e := range d.Added {
+		lines[e.Id] = []string{"+ " + e.Id + "@" + e.Version}
+	}
+	for _, c := range d.Changed {
+		oldLine, newLine := "- "+c.Old.Id+"@"+c.Old.Version, "+ "+c.New.Id+"@"+c.New.Version
+		if c.Old.Path != c.New.Path {
+			oldLine += " " + c.Old.Path
+			newLine += " " + c.New.Path
+		}
+		lines[c.New.Id] = []string{oldLine, newLine}
+	}
+	ids := make([]string, 0, len(lines))
+	for id := range lines {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		for _, line := range lines[id] {
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return skerr.Wrap(err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+// exportTestEntries returns a small set of committed entries covering git and
+// CIPD dependencies.
+func exportTestEntries(t *testing.T) deps_parser.DepsEntries {
+	rv := deps_parser.DepsEntries{}
+	for _, id := range []string{
+		"chromium.googlesource.com/chromium/deps/libjpeg_turbo",
+		"chromium.googlesource.com/external/github.com/harfbuzz/harfbuzz",
+		"infra/3pp/tools/ninja",
+	} {
+		e, err := Get(id)
+		require.NoError(t, err)
+		rv[id] = e
+	}
+	return rv
+}
+
+func TestWriteGNArgs_MatchesGolden(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteGNArgs(&buf, exportTestEntries(t)))
+	assert.Equal(t, `skia_libjpeg_turbo_rev = "ccfbe1c82a3b6dbe8647ceb36a3f9ee711fba3cf"
+skia_harfbuzz_rev = "a070f9ebbe88dc71b248af9731dd49ec93f4e6e6"
+# skia_ninja_rev = "version:2@1.12.1.chromium.4"
+`, buf.String())
+}
+
+func TestWriteYAML_MatchesGolden(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteYAML(&buf, exportTestEntries(t)))
+	assert.Equal(t, `- id: "chromium.googlesource.com/chromium/deps/libjpeg_turbo"
+  path: "third_party/externals/libjpeg-turbo"
+  version: "ccfbe1c82a3b6dbe8647ceb36a3f9ee711fba3cf"
+  scheme: git
+- id: "chromium.googlesource.com/external/github.com/harfbuzz/harfbuzz"
+  path: "third_party/externals/harfbuzz"
+  version: "a070f9ebbe88dc71b248af9731dd49ec93f4e6e6"
+  scheme: git
+- id: "infra/3pp/tools/ninja"
+  path: "bin"
+  version: "version:2@1.12.1.chromium.4"
+  scheme: cipd
+`, buf.String())
+}
+
+func TestWriteShellVars_MatchesGolden(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteShellVars(&buf, exportTestEntries(t)))
+	assert.Equal(t, `SKIA_DEP_LIBJPEG_TURBO='ccfbe1c82a3b6dbe8647ceb36a3f9ee711fba3cf'
+SKIA_DEP_HARFBUZZ='a070f9ebbe88dc71b248af9731dd49ec93f4e6e6'
+SKIA_DEP_NINJA='version:2@1.12.1.chromium.4'
+`, buf.String())
+}
+
+func TestWriteShellVars_QuotesSingleQuotes(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteShellVars(&buf, deps_parser.DepsEntries{
+		"x": {Id: "x", Version: "it's", Path: "third_party/x"},
+	}))
+	assert.Equal(t, "SKIA_DEP_X='it'\\''s'\n", buf.String())
+}
+
+func TestShellVarName_ValidIdentifiers(t *testing.T) {
+	for _, e := range deps {
+		assert.Regexp(t, `^[A-Za-z_][A-Za-z0-9_]*$`, shellVarName(*e))
+	}
+}
+
+func TestWriteStarlark_MatchesGolden(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteStarlark(&buf, exportTestEntries(t), "SKIA_DEPS"))
+	assert.Equal(t, `SKIA_DEPS = {
+    "chromium.googlesource.com/chromium/deps/libjpeg_turbo": {"path": "third_party/externals/libjpeg-turbo", "version": "ccfbe1c82a3b6dbe8647ceb36a3f9ee711fba3cf"},
+    "chromium.googlesource.com/external/github.com/harfbuzz/harfbuzz": {"path": "third_party/externals/harfbuzz", "version": "a070f9ebbe88dc71b248af9731dd49ec93f4e6e6"},
+    "infra/3pp/tools/ninja": {"path": "bin", "version": "version:2@1.12.1.chromium.4"},
+}
+`, buf.String())
+}
+
+func TestWriteStarlark_SyntacticallyConsistent(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteStarlark(&buf, deps, "DEPS"))
+	out := buf.String()
+	assert.Equal(t, strings.Count(out, "{"), strings.Count(out, "}"))
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	require.Len(t, lines, len(deps)+2)
+	assert.Equal(t, "DEPS = {", lines[0])
+	assert.Equal(t, "}", lines[len(lines)-1])
+	for _, line := range lines[1 : len(lines)-1] {
+		assert.True(t, strings.HasPrefix(line, `    "`), line)
+		assert.True(t, strings.HasSuffix(line, "},"), line)
+	}
+}
+
+func TestWriteStarlark_InvalidVarName(t *testing.T) {
+	var buf bytes.Buffer
+	require.Error(t, WriteStarlark(&buf, deps, "1DEPS"))
+	assert.Empty(t, buf.String())
+}
+
+func TestWriteSubmoduleScript_MatchesGolden(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteSubmoduleScript(&buf, exportTestEntries(t)))
+	assert.Equal(t, `# Skipping CIPD package infra/3pp/tools/ninja at bin
+git submodule add 'https://chromium.googlesource.com/external/github.com/harfbuzz/harfbuzz' 'third_party/externals/harfbuzz'
+git -C 'third_party/externals/harfbuzz' checkout a070f9ebbe88dc71b248af9731dd49ec93f4e6e6
+git submodule add 'https://chromium.googlesource.com/chromium/deps/libjpeg_turbo' 'third_party/externals/libjpeg-turbo'
+git -C 'third_party/externals/libjpeg-turbo' checkout ccfbe1c82a3b6dbe8647ceb36a3f9ee711fba3cf
+`, buf.String())
+}
+
+func TestWritePinnedList_MatchesGolden(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WritePinnedList(&buf, exportTestEntries(t)))
+	assert.Equal(t, `chromium.googlesource.com/chromium/deps/libjpeg_turbo ccfbe1c82a3b6dbe8647ceb36a3f9ee711fba3cf
+chromium.googlesource.com/external/github.com/harfbuzz/harfbuzz a070f9ebbe88dc71b248af9731dd49ec93f4e6e6
+infra/3pp/tools/ninja version:2@1.12.1.chromium.4
+`, buf.String())
+}
+
+func TestWritePinnedList_StableOrder(t *testing.T) {
+	var first bytes.Buffer
+	require.NoError(t, WritePinnedList(&first, deps))
+	for i := 0; i < 10; i++ {
+		var buf bytes.Buffer
+		require.NoError(t, WritePinnedList(&buf, Entries()))
+		require.Equal(t, first.String(), buf.String())
+	}
+	lines := strings.Split(strings.TrimSuffix(first.String(), "\n"), "\n")
+	require.Len(t, lines, len(deps))
+	assert.True(t, sort.StringsAreSorted(lines))
+	assert.NotContains(t, first.String(), "\r")
+}
+
+func TestWriteManifest_MatchesGolden(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteManifest(&buf, exportTestEntries(t)))
+	assert.Equal(t, "bin\tversion:2@1.12.1.chromium.4\n"+
+		"third_party/externals/harfbuzz\ta070f9ebbe88dc71b248af9731dd49ec93f4e6e6\n"+
+		"third_party/externals/libjpeg-turbo\tccfbe1c82a3b6dbe8647ceb36a3f9ee711fba3cf\n", buf.String())
+}
+
+func TestWriteLockDelta_MatchesGolden(t *testing.T) {
+	old := deps_parser.DepsEntries{
+		"a": {Id: "a", Version: "version:2@1.0", Path: "bin"},
+		"b": {Id: "b", Version: "version:2@1.0", Path: "bin"},
+		"c": {Id: "c", Version: "version:2@1.0", Path: "bin"},
+		"d": {Id: "d", Version: "version:2@1.0", Path: "bin"},
+	}
+	new := deps_parser.DepsEntries{
+		"a": {Id: "a", Version: "version:2@1.1", Path: "bin"},
+		"c": {Id: "c", Version: "version:2@1.0", Path: "bin"},
+		"d": {Id: "d", Version: "version:2@1.0", Path: "tools"},
+		"e": {Id: "e", Version: "version:2@1.0", Path: "bin"},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, WriteLockDelta(&buf, old, new))
+	assert.Equal(t, `- a@version:2@1.0
++ a@version:2@1.1
+- b@version:2@1.0
+- d@version:2@1.0 bin
++ d@version:2@1.0 tools
++ e@version:2@1.0
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, WriteLockDelta(&buf, deps, deps))
+	assert.Empty(t, buf.String())
+}
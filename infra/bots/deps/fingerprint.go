@@ -0,0 +1,66 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+// Fingerprint returns a hex-encoded SHA-256 hash of the given entries, which
+// changes whenever any entry is added, removed or has its Version or Path
+// changed, regardless of map order.
+func Fingerprint(entries deps_parser.DepsEntries) string {
+	h := sha256.New()
+	for _, e := range sortedEntries(entries) {
+		// Fields cannot contain NUL, so this is unambiguous.
+		_, _ = fmt.Fprintf(h, "%s\x00%s\x00%s\n", e.Id, e.Version, e.Path)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// externalsDir is the directory into which third-party libraries are synced.
+const externalsDir = "third_party/externals"
+
+// ExternalsRollup returns a pseudo-entry standing for every dependency synced
+// into third_party/externals, whose Version is the Fingerprint of those
+// entries and so changes whenever any of them does.
+func ExternalsRollup() deps_parser.DepsEntry {
+	return externalsRollup(deps)
+}
+
+func externalsRollup(entries deps_parser.DepsEntries) deps_parser.DepsEntry {
+	externals := deps_parser.DepsEntries{}
+	for id, e := range entries {
+		if strings.HasPrefix(e.Path, externalsDir+"/") {
+			externals[id] = e
+		}
+	}
+	return deps_parser.DepsEntry{
+		Id:      externalsDir,
+		Version: Fingerprint(externals),
+		Path:    externalsDir,
+	}
+}
+
+// ToolingFingerprint returns a hex-encoded SHA-256 hash of the IDs and versions
+// of the IsTooling dependencies, which identifies the toolchain used for a
+// build. Unlike Fingerprint, it ignores Paths and every library.
+func ToolingFingerprint() string {
+	return toolingFingerprint(deps)
+}
+
+func toolingFingerprint(entries deps_parser.DepsEntries) string {
+	h := sha256.New()
+	for _, e := range filterEntries(entries, IsTooling) {
+		_, _ = fmt.Fprintf(h, "%s|%s\n", e.Id, e.Version)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+func TestFingerprint_StableAndSensitive(t *testing.T) {
+	assert.Equal(t, Fingerprint(deps), Fingerprint(Entries()))
+	assert.Len(t, Fingerprint(deps), 64)
+
+	a := deps_parser.DepsEntries{"a": {Id: "a", Version: "1", Path: "x"}}
+	b := deps_parser.DepsEntries{"a": {Id: "a", Version: "1", Path: "y"}}
+	c := deps_parser.DepsEntries{"a": {Id: "a", Version: "2", Path: "x"}}
+	assert.NotEqual(t, Fingerprint(a), Fingerprint(b))
+	assert.NotEqual(t, Fingerprint(a), Fingerprint(c))
+	assert.NotEqual(t, Fingerprint(a), Fingerprint(deps_parser.DepsEntries{}))
+}
+
+func TestExternalsRollup_ChangesWithAnyExternal(t *testing.T) {
+	rollup := ExternalsRollup()
+	assert.Equal(t, "third_party/externals", rollup.Path)
+
+	for id, e := range deps {
+		entries := Entries()
+		entries[id].Version = "version:2@changed"
+		changed := externalsRollup(entries).Version != rollup.Version
+		assert.Equal(t, strings.HasPrefix(e.Path, "third_party/externals/"), changed, id)
+	}
+}
+
+func TestToolingFingerprint(t *testing.T) {
+	fingerprint := ToolingFingerprint()
+	assert.Len(t, fingerprint, 64)
+	assert.Equal(t, fingerprint, toolingFingerprint(Entries()))
+
+	entries := Entries()
+	entries["chromium.googlesource.com/external/github.com/harfbuzz/harfbuzz"].Version = newHash
+	entries["chromium.googlesource.com/external/github.com/harfbuzz/harfbuzz"].Path = "third_party/harfbuzz"
+	assert.Equal(t, fingerprint, toolingFingerprint(entries), "library changes are ignored")
+
+	entries["infra/3pp/tools/ninja"].Version = "version:2@1.12.2"
+	assert.NotEqual(t, fingerprint, toolingFingerprint(entries))
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+	"go.skia.org/infra/go/skerr"
+)
+
+// ANSI escape sequences used by FormatEntryColor.
+const (
+	ansiReset = "\033[0m"
+	ansiRed   = "\033[31m"
+	ansiGreen = "\033[32m"
+	ansiCyan  = "\033[36m"
+)
+
+// HostColor returns a CSS hex color, eg. "#1a2b3c", derived from a hash of the
+// given host, so that each host is consistently displayed in the same color.
+func HostColor(host string) string {
+	sum := sha256.Sum256([]byte(host))
+	return fmt.Sprintf("#%02x%02x%02x", sum[0], sum[1], sum[2])
+}
+
+// FormatEntryColor returns a single-line, human-readable description of the
+// given entry. If color is true, the version scheme and version are wrapped in
+// ANSI color codes: green for git, cyan for CIPD and red for anything else.
+func FormatEntryColor(e deps_parser.DepsEntry, color bool) string {
+	kind := ClassifyVersion(e.Version)
+	scheme, version := kind.String(), e.Version
+	if color {
+		c := ansiRed
+		switch kind {
+		case VersionGit:
+			c = ansiGreen
+		case VersionCIPD:
+			c = ansiCyan
+		}
+		scheme = c + scheme + ansiReset
+		version = c + version + ansiReset
+	}
+	return fmt.Sprintf("%s [%s] %s -> %s", e.Id, scheme, version, e.Path)
+}
+
+// WriteDOT writes the given entries as a Graphviz DOT digraph, with one node
+// per entry and an edge from each entry to every entry it Requires. Entries
+// with no relationships appear as isolated nodes.
+func WriteDOT(w io.Writer, entries deps_parser.DepsEntries) error {
+	sorted := sortedEntries(entries)
+	if _, err := fmt.Fprintln(w, "digraph deps {"); err != nil {
+		return skerr.Wrap(err)
+	}
+	for _, e := range sorted {
+		if _, err := fmt.Fprintf(w, "  %q;\n", e.Id); err != nil {
+			return skerr.Wrap(err)
+		}
+	}
+	for _, e := range sorted {
+		for _, req := range Requires(e) {
+			if _, ok := entries[req]; !ok {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "  %q -> %q;\n", e.Id, req); err != nil {
+				return skerr.Wrap(err)
+			}
+		}
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return skerr.Wrap(err)
+}
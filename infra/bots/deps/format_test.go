@@ -0,0 +1,69 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+func TestFormatEntryColor_NoColor_PlainText(t *testing.T) {
+	e := deps_parser.DepsEntry{
+		Id:      "chromium.googlesource.com/chromium/deps/icu",
+		Version: "364118a1d9da24bb5b770ac3d762ac144d6da5a4",
+		Path:    "third_party/externals/icu",
+	}
+	assert.Equal(t, "chromium.googlesource.com/chromium/deps/icu [git] 364118a1d9da24bb5b770ac3d762ac144d6da5a4 -> third_party/externals/icu", FormatEntryColor(e, false))
+}
+
+func TestFormatEntryColor_Color_UsesSchemeColor(t *testing.T) {
+	test := func(name, version, color string) {
+		t.Run(name, func(t *testing.T) {
+			e := deps_parser.DepsEntry{Id: "some/dep", Version: version, Path: "some/path"}
+			assert.NotContains(t, FormatEntryColor(e, false), "\033[")
+			assert.Contains(t, FormatEntryColor(e, true), color+version+ansiReset)
+		})
+	}
+	test("git", "364118a1d9da24bb5b770ac3d762ac144d6da5a4", ansiGreen)
+	test("cipd", "version:2@1.12.1.chromium.4", ansiCyan)
+	test("unknown", "main", ansiRed)
+}
+
+func TestHostColor(t *testing.T) {
+	color := HostColor("chromium.googlesource.com")
+	assert.Regexp(t, `^#[0-9a-f]{6}$`, color)
+	assert.Equal(t, color, HostColor("chromium.googlesource.com"))
+	assert.NotEqual(t, color, HostColor("dawn.googlesource.com"))
+}
+
+func TestWriteDOT_RelatedSet_MatchesGolden(t *testing.T) {
+	entries := deps_parser.DepsEntries{}
+	for _, id := range []string{
+		"dawn.googlesource.com/dawn",
+		"chromium.googlesource.com/chromium/src/third_party/jinja2",
+		"chromium.googlesource.com/chromium/src/third_party/markupsafe",
+		"chromium.googlesource.com/chromium/deps/icu",
+	} {
+		e, err := Get(id)
+		require.NoError(t, err)
+		entries[id] = e
+	}
+	var buf bytes.Buffer
+	require.NoError(t, WriteDOT(&buf, entries))
+	assert.Equal(t, `digraph deps {
+  "chromium.googlesource.com/chromium/deps/icu";
+  "chromium.googlesource.com/chromium/src/third_party/jinja2";
+  "chromium.googlesource.com/chromium/src/third_party/markupsafe";
+  "dawn.googlesource.com/dawn";
+  "dawn.googlesource.com/dawn" -> "chromium.googlesource.com/chromium/src/third_party/jinja2";
+  "dawn.googlesource.com/dawn" -> "chromium.googlesource.com/chromium/src/third_party/markupsafe";
+}
+`, buf.String())
+}
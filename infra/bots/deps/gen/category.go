@@ -0,0 +1,38 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package gen
+
+import (
+	"strings"
+
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+// toolingIds are the IDs of dependencies which provide build and CI tooling
+// rather than libraries compiled into Skia.
+var toolingIds = map[string]bool{
+	"chromium.googlesource.com/chromium/src/buildtools": true,
+	"infra/3pp/tools/ninja":                             true,
+	"skia/tools/bazel_build":                            true,
+	"skia/tools/sk":                                     true,
+}
+
+// IsTooling returns true if the given entry provides build or CI tooling rather
+// than a library.
+func IsTooling(e deps_parser.DepsEntry) bool {
+	return toolingIds[e.Id]
+}
+
+// Host returns the host portion of the given entry's ID, eg.
+// "chromium.googlesource.com", or the empty string if the ID has no host, as is
+// the case for CIPD packages.
+func Host(e deps_parser.DepsEntry) string {
+	host, _, _ := strings.Cut(e.Id, "/")
+	if !strings.Contains(host, ".") {
+		return ""
+	}
+	return host
+}
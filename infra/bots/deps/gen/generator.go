@@ -0,0 +1,322 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package gen parses DEPS and generates deps_gen.go from it. It must not depend
+// on the deps package, so that deps_gen.go can be regenerated even when it is
+// missing or does not compile.
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+	"go.skia.org/infra/go/skerr"
+)
+
+// GenerateOptions control the output of Generate.
+type GenerateOptions struct {
+	// GroupComments groups the entries by host, preceding each group with a
+	// comment like "// --- chromium.googlesource.com ---".
+	GroupComments bool
+	// ToolingOnly omits every entry which is not IsTooling, eg. for a minimal
+	// bootstrap package.
+	ToolingOnly bool
+	// EmitGoGenerate adds a goGenerateDirective to the top of the file, so that
+	// the command which regenerates it is self-documenting.
+	EmitGoGenerate bool
+}
+
+// generatedHeader is the preamble of the generated Go file.
+const generatedHeader = `// Code generated by "go run generate.go"; DO NOT EDIT
+
+package deps
+
+import (
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+`
+
+// goGenerateDirective is the directive added by GenerateOptions.EmitGoGenerate.
+// It runs generate.go with the repo's hermetic Go toolchain.
+const goGenerateDirective = "//go:generate bazelisk run //:go -- run ./generate.go"
+
+// DepsGenOptions are the options used to generate the committed deps_gen.go.
+// It carries the package's only go:generate directive.
+var DepsGenOptions = GenerateOptions{EmitGoGenerate: true}
+
+// cipdGroup is the group comment used for entries which have no host.
+const cipdGroup = "cipd"
+
+// Generate writes Go source code declaring the given entries in the same form
+// as deps_gen.go. The output is gofmt-clean.
+func Generate(w io.Writer, entries deps_parser.DepsEntries, opts GenerateOptions) error {
+	sorted := sortedEntries(entries)
+	if opts.GroupComments {
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return groupKey(sorted[i]) < groupKey(sorted[j])
+		})
+	}
+
+	var buf bytes.Buffer
+	header := generatedHeader
+	if opts.EmitGoGenerate {
+		header = strings.Replace(header, "\n\npackage deps", "\n\n"+goGenerateDirective+"\n\npackage deps", 1)
+	}
+	buf.WriteString(header)
+	buf.WriteString("var deps = deps_parser.DepsEntries{\n")
+	prevGroup := ""
+	for _, e := range sorted {
+		if opts.ToolingOnly && !IsTooling(e) {
+			continue
+		}
+		if opts.GroupComments {
+			if group := groupName(e); group != prevGroup {
+				fmt.Fprintf(&buf, "// --- %s ---\n", group)
+				prevGroup = group
+			}
+		}
+		fmt.Fprintf(&buf, "%q: {\nId: %q,\nVersion: %q,\nPath: %q,\n", e.Id, e.Id, e.Version, e.Path)
+		if e.Type != "" {
+			fmt.Fprintf(&buf, "Type: %s,\n", depTypeExpr(e.Type))
+		}
+		buf.WriteString("},\n")
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return skerr.Wrapf(err, "formatting generated code")
+	}
+	if HasTrailingWhitespace(src) {
+		return skerr.Fmt("generated code contains trailing whitespace")
+	}
+	_, err = w.Write(src)
+	return skerr.Wrap(err)
+}
+
+// depTypeConsts maps each known DepType to the name of its constant in the
+// deps_parser package.
+var depTypeConsts = map[deps_parser.DepType]string{
+	deps_parser.DepType_Git:  "DepType_Git",
+	deps_parser.DepType_Cipd: "DepType_Cipd",
+	deps_parser.DepType_Gcs:  "DepType_Gcs",
+}
+
+// depTypeExpr returns the Go expression for the given DepType in generated
+// code, preferring the named constant where one exists.
+func depTypeExpr(t deps_parser.DepType) string {
+	if name, ok := depTypeConsts[t]; ok {
+		return "deps_parser." + name
+	}
+	return fmt.Sprintf("%q", string(t))
+}
+
+// depsParserPath is the import path of the deps_parser package, which is the
+// only import allowed in generated code.
+const depsParserPath = "go.skia.org/infra/go/depot_tools/deps_parser"
+
+// depsParserImporter imports the real deps_parser package from source, in the
+// module containing the current directory, so that generated code is checked
+// against the package's actual declarations. Every other import is refused.
+// The package is loaded at most once.
+type depsParserImporter struct {
+	mtx sync.Mutex
+	pkg *types.Package
+}
+
+// sourceImporter is the depsParserImporter shared by all type checks.
+var sourceImporter = &depsParserImporter{}
+
+// Import implements types.Importer.
+func (i *depsParserImporter) Import(path string) (*types.Package, error) {
+	if path != depsParserPath {
+		return nil, skerr.Fmt("generated code may not import %q", path)
+	}
+	i.mtx.Lock()
+	defer i.mtx.Unlock()
+	if i.pkg == nil {
+		pkg, err := importer.ForCompiler(token.NewFileSet(), "source", nil).Import(path)
+		if err != nil {
+			return nil, skerr.Wrapf(err, "loading %s", path)
+		}
+		i.pkg = pkg
+	}
+	return i.pkg, nil
+}
+
+// ValidateGeneratedSource parses and type-checks the given generated Go source
+// in memory, returning an error if it would not compile or does not declare
+// the deps variable as deps_parser.DepsEntries.
+func ValidateGeneratedSource(src []byte) error {
+	_, _, err := checkGeneratedSource(src)
+	return err
+}
+
+// ParseGeneratedSource returns the entries declared by the given generated Go
+// source, which must pass ValidateGeneratedSource, without compiling it. Every
+// field of every entry must be a constant.
+func ParseGeneratedSource(src []byte) (deps_parser.DepsEntries, error) {
+	f, info, err := checkGeneratedSource(src)
+	if err != nil {
+		return nil, skerr.Wrap(err)
+	}
+	var lit *ast.CompositeLit
+	ast.Inspect(f, func(n ast.Node) bool {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok {
+			return lit == nil
+		}
+		for i, name := range spec.Names {
+			if name.Name == "deps" && i < len(spec.Values) {
+				lit, _ = spec.Values[i].(*ast.CompositeLit)
+			}
+		}
+		return false
+	})
+	if lit == nil {
+		return nil, skerr.Fmt("generated deps is not initialized with a composite literal")
+	}
+	rv := make(deps_parser.DepsEntries, len(lit.Elts))
+	for _, elt := range lit.Elts {
+		kv := elt.(*ast.KeyValueExpr)
+		key, err := constantString(info, kv.Key)
+		if err != nil {
+			return nil, skerr.Wrap(err)
+		}
+		fields, ok := kv.Value.(*ast.CompositeLit)
+		if !ok {
+			return nil, skerr.Fmt("entry %q is not a composite literal", key)
+		}
+		e := &deps_parser.DepsEntry{}
+		for _, field := range fields.Elts {
+			fieldKV, ok := field.(*ast.KeyValueExpr)
+			if !ok {
+				return nil, skerr.Fmt("entry %q has an unkeyed field", key)
+			}
+			val, err := constantString(info, fieldKV.Value)
+			if err != nil {
+				return nil, skerr.Wrapf(err, "entry %q", key)
+			}
+			switch name := fieldKV.Key.(*ast.Ident).Name; name {
+			case "Id":
+				e.Id = val
+			case "Version":
+				e.Version = val
+			case "Path":
+				e.Path = val
+			case "Type":
+				e.Type = deps_parser.DepType(val)
+			default:
+				return nil, skerr.Fmt("entry %q has unknown field %s", key, name)
+			}
+		}
+		rv[key] = e
+	}
+	return rv, nil
+}
+
+// SemanticallyEqual returns true if the given sets contain the same entries,
+// regardless of ordering or of the keys used in the maps.
+func SemanticallyEqual(a, b deps_parser.DepsEntries) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA, sortedB := sortedEntries(a), sortedEntries(b)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedEntries returns copies of the given entries, sorted by ID.
+func sortedEntries(entries deps_parser.DepsEntries) []deps_parser.DepsEntry {
+	rv := make([]deps_parser.DepsEntry, 0, len(entries))
+	for _, e := range entries {
+		rv = append(rv, *e)
+	}
+	sort.Slice(rv, func(i, j int) bool {
+		return rv[i].Id < rv[j].Id
+	})
+	return rv
+}
+
+// checkGeneratedSource implements ValidateGeneratedSource, returning the
+// parsed file and the type information for its expressions.
+func checkGeneratedSource(src []byte) (*ast.File, *types.Info, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "deps_gen.go", src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, skerr.Wrapf(err, "parsing generated code")
+	}
+	if f.Name.Name != "deps" {
+		return nil, nil, skerr.Fmt("generated code declares package %q, not \"deps\"", f.Name.Name)
+	}
+	conf := types.Config{Importer: sourceImporter}
+	info := &types.Info{Types: map[ast.Expr]types.TypeAndValue{}}
+	pkg, err := conf.Check("go.skia.org/skia/infra/bots/deps", fset, []*ast.File{f}, info)
+	if err != nil {
+		return nil, nil, skerr.Wrapf(err, "type-checking generated code")
+	}
+	obj := pkg.Scope().Lookup("deps")
+	if obj == nil {
+		return nil, nil, skerr.Fmt("generated code does not declare deps")
+	}
+	if _, ok := obj.(*types.Var); !ok || obj.Type().String() != depsParserPath+".DepsEntries" {
+		return nil, nil, skerr.Fmt("generated deps has type %s, not deps_parser.DepsEntries", obj.Type())
+	}
+	return f, info, nil
+}
+
+// constantString returns the value of the given string constant expression.
+func constantString(info *types.Info, expr ast.Expr) (string, error) {
+	val := info.Types[expr].Value
+	if val == nil || val.Kind() != constant.String {
+		return "", skerr.Fmt("%s is not a string constant", types.ExprString(expr))
+	}
+	return constant.StringVal(val), nil
+}
+
+// HasTrailingWhitespace returns true if any line of the given text ends in a
+// space or tab. gofmt tolerates such lines inside comments.
+func HasTrailingWhitespace(b []byte) bool {
+	for _, line := range bytes.Split(b, []byte("\n")) {
+		line = bytes.TrimSuffix(line, []byte("\r"))
+		if len(line) > 0 && (line[len(line)-1] == ' ' || line[len(line)-1] == '\t') {
+			return true
+		}
+	}
+	return false
+}
+
+// groupName returns the name used in the group comment for the given entry.
+func groupName(e deps_parser.DepsEntry) string {
+	if host := Host(e); host != "" {
+		return host
+	}
+	return cipdGroup
+}
+
+// groupKey orders groups by host, with CIPD packages last.
+func groupKey(e deps_parser.DepsEntry) string {
+	if host := Host(e); host != "" {
+		return "0" + host
+	}
+	return "1"
+}
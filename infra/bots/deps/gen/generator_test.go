@@ -0,0 +1,208 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package gen
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+// depsGenPath is the location of the committed generated file.
+const depsGenPath = "../deps_gen.go"
+
+// committedEntries returns the entries declared in the committed generated file.
+func committedEntries(t *testing.T) deps_parser.DepsEntries {
+	src, err := os.ReadFile(depsGenPath)
+	require.NoError(t, err)
+	entries, err := ParseGeneratedSource(src)
+	require.NoError(t, err)
+	return entries
+}
+
+func TestGenerate_DepsGenOptions_MatchesDepsGen(t *testing.T) {
+	expect, err := os.ReadFile(depsGenPath)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, Generate(&buf, committedEntries(t), DepsGenOptions))
+	assert.Equal(t, string(expect), buf.String())
+}
+
+func TestGenerate_GroupComments_HostCommentPrecedesFirstEntry(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Generate(&buf, committedEntries(t), GenerateOptions{GroupComments: true}))
+	out := buf.String()
+
+	for _, group := range []string{"chromium.googlesource.com", "dawn.googlesource.com", "cipd"} {
+		assert.Equal(t, 1, strings.Count(out, "// --- "+group+" ---"), group)
+	}
+	comment := strings.Index(out, "\t// --- dawn.googlesource.com ---\n")
+	entry := strings.Index(out, "\t\"dawn.googlesource.com/dawn\": {\n")
+	require.NotEqual(t, -1, comment)
+	require.NotEqual(t, -1, entry)
+	assert.Less(t, comment, entry)
+	assert.Equal(t, comment+len("\t// --- dawn.googlesource.com ---\n"), entry)
+}
+
+func TestGenerate_ToolingOnly_OmitsExternals(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Generate(&buf, committedEntries(t), GenerateOptions{ToolingOnly: true}))
+	out := buf.String()
+
+	assert.NotContains(t, out, "third_party/externals")
+	assert.Equal(t, 4, strings.Count(out, "Id: "))
+	for _, id := range []string{
+		"chromium.googlesource.com/chromium/src/buildtools",
+		"infra/3pp/tools/ninja",
+		"skia/tools/bazel_build",
+		"skia/tools/sk",
+	} {
+		assert.Contains(t, out, "\t\""+id+"\": {\n")
+	}
+}
+
+func TestGenerate_EmitGoGenerate_DirectiveAtTop(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Generate(&buf, committedEntries(t), GenerateOptions{EmitGoGenerate: true}))
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, `// Code generated by "go run generate.go"; DO NOT EDIT
+
+//go:generate bazelisk run //:go -- run ./generate.go
+
+package deps
+`))
+	assert.NoError(t, ValidateGeneratedSource(buf.Bytes()))
+
+	buf.Reset()
+	require.NoError(t, Generate(&buf, committedEntries(t), GenerateOptions{}))
+	assert.NotContains(t, buf.String(), "//go:generate")
+}
+
+func TestHasTrailingWhitespace(t *testing.T) {
+	assert.False(t, HasTrailingWhitespace([]byte("package deps\n\n// Comment.\n")))
+	assert.False(t, HasTrailingWhitespace([]byte("")))
+	assert.True(t, HasTrailingWhitespace([]byte("package deps\n\n// Comment. \n")))
+	assert.True(t, HasTrailingWhitespace([]byte("package deps\t\n")))
+	assert.True(t, HasTrailingWhitespace([]byte("// No newline at end ")))
+	assert.True(t, HasTrailingWhitespace([]byte("// CRLF \r\n")))
+
+	genFile, err := os.ReadFile(depsGenPath)
+	require.NoError(t, err)
+	assert.False(t, HasTrailingWhitespace(genFile))
+}
+
+func TestValidateGeneratedSource_GeneratorOutput_Valid(t *testing.T) {
+	for _, opts := range []GenerateOptions{{}, {GroupComments: true}, {ToolingOnly: true}} {
+		var buf bytes.Buffer
+		require.NoError(t, Generate(&buf, committedEntries(t), opts))
+		assert.NoError(t, ValidateGeneratedSource(buf.Bytes()), "%+v", opts)
+	}
+}
+
+func TestGenerate_Type(t *testing.T) {
+	entries := deps_parser.DepsEntries{
+		"skia-data/data.tar.gz": {Id: "skia-data/data.tar.gz", Version: "abc", Path: "data", Type: deps_parser.DepType_Gcs},
+		"example/other":         {Id: "example/other", Version: "abc", Path: "other", Type: "other"},
+		"example/untyped":       {Id: "example/untyped", Version: "abc", Path: "untyped"},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, Generate(&buf, entries, GenerateOptions{}))
+	assert.Contains(t, buf.String(), "Type:    deps_parser.DepType_Gcs,\n")
+	assert.Contains(t, buf.String(), "Type:    \"other\",\n")
+	assert.Equal(t, 2, strings.Count(buf.String(), "Type:"))
+	assert.NoError(t, ValidateGeneratedSource(buf.Bytes()))
+}
+
+func TestDepTypeConsts_MatchDepsParser(t *testing.T) {
+	// ParseGeneratedSource reads the Type of each entry from the constants
+	// declared by the real deps_parser package, so a name in depTypeConsts
+	// which is undeclared, or which names the wrong constant, fails the round
+	// trip.
+	entries := deps_parser.DepsEntries{}
+	for depType := range depTypeConsts {
+		id := "example/" + string(depType)
+		entries[id] = &deps_parser.DepsEntry{Id: id, Version: "abc", Path: id, Type: depType}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, Generate(&buf, entries, GenerateOptions{}))
+	actual, err := ParseGeneratedSource(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, entries, actual)
+}
+
+func TestValidateGeneratedSource_Broken(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Generate(&buf, committedEntries(t), GenerateOptions{ToolingOnly: true}))
+	src := buf.String()
+
+	test := func(name, src, expectErr string) {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateGeneratedSource([]byte(src))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), expectErr)
+		})
+	}
+	test("syntax error", strings.Replace(src, "},\n}", "},\n", 1), "parsing generated code")
+	test("unknown field", strings.Replace(src, "Version:", "Revision:", 1), "unknown field Revision")
+	test("wrong field type", strings.Replace(src, `Path:    "bin"`, `Path:    42`, 1), "type-checking generated code")
+	test("unknown constant", strings.Replace(src, "deps_parser.DepType_Cipd", "deps_parser.DepType_Bogus", 1), "type-checking generated code")
+	test("duplicate key", strings.Replace(src, `"skia/tools/sk"`, `"skia/tools/bazel_build"`, 1), "duplicate key")
+	test("wrong package", strings.Replace(src, "package deps", "package other", 1), `declares package "other"`)
+	test("unexpected import", strings.Replace(src, `"go.skia.org/infra/go/depot_tools/deps_parser"`, `"go.skia.org/infra/go/depot_tools/deps_parser"
+	_ "os"`, 1), `may not import "os"`)
+	test("missing deps", strings.Replace(src, "var deps =", "var other =", 1), "does not declare deps")
+}
+
+func TestParseGeneratedSource_RoundTrip(t *testing.T) {
+	entries := deps_parser.DepsEntries{
+		"skia-data/data.tar.gz": {Id: "skia-data/data.tar.gz", Version: "abc", Path: "data", Type: deps_parser.DepType_Gcs},
+		"example/other":         {Id: "example/other", Version: "abc", Path: "other", Type: "other"},
+		"example/untyped":       {Id: "example/untyped", Version: "abc", Path: "untyped"},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, Generate(&buf, entries, GenerateOptions{GroupComments: true}))
+	actual, err := ParseGeneratedSource(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, entries, actual)
+}
+
+func TestParseGeneratedSource_Broken(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Generate(&buf, committedEntries(t), GenerateOptions{ToolingOnly: true}))
+	src := buf.String()
+
+	_, err := ParseGeneratedSource([]byte(strings.Replace(src, "Version:", "Revision:", 1)))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown field Revision")
+
+	_, err = ParseGeneratedSource([]byte(strings.Replace(src, `Path:    "bin"`, `Path:    bin`, 1) + "\nvar bin = \"bin\"\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bin is not a string constant")
+}
+
+func TestSemanticallyEqual(t *testing.T) {
+	a := deps_parser.DepsEntries{
+		"x": {Id: "x", Version: "1", Path: "x"},
+		"y": {Id: "y", Version: "1", Path: "y"},
+	}
+	reordered := deps_parser.DepsEntries{
+		"https://y.git": {Id: "y", Version: "1", Path: "y"},
+		"x":             {Id: "x", Version: "1", Path: "x"},
+	}
+	different := deps_parser.DepsEntries{
+		"x": {Id: "x", Version: "1", Path: "x"},
+		"y": {Id: "y", Version: "2", Path: "y"},
+	}
+	assert.True(t, SemanticallyEqual(a, reordered))
+	assert.False(t, SemanticallyEqual(a, different))
+	assert.False(t, SemanticallyEqual(a, deps_parser.DepsEntries{"x": a["x"]}))
+}
@@ -0,0 +1,37 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package gen
+
+import (
+	"strings"
+
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+	"go.skia.org/infra/go/skerr"
+)
+
+// ParseDeps parses the given DEPS file contents, as deps_parser.ParseDeps.
+// CRLF and lone CR line endings, which deps_parser rejects, are converted to
+// LF first, and surrounding whitespace is trimmed from every field. Entries may
+// use either the "url@rev" string shorthand or the dict form with explicit
+// "url" and "dep_type" keys; both produce the same DepsEntry, with the
+// dep_type recorded in its Type.
+func ParseDeps(contents string) (deps_parser.DepsEntries, error) {
+	contents = strings.ReplaceAll(contents, "\r\n", "\n")
+	contents = strings.ReplaceAll(contents, "\r", "\n")
+	parsed, err := deps_parser.ParseDeps(contents)
+	if err != nil {
+		return nil, skerr.Wrap(err)
+	}
+	rv := make(deps_parser.DepsEntries, len(parsed))
+	for _, e := range parsed {
+		cp := *e
+		cp.Id = strings.TrimSpace(cp.Id)
+		cp.Version = strings.TrimSpace(cp.Version)
+		cp.Path = strings.TrimSpace(cp.Path)
+		rv[cp.Id] = &cp
+	}
+	return rv, nil
+}
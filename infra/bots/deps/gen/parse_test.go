@@ -0,0 +1,88 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package gen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+const testDEPS = `use_relative_paths = True
+
+deps = {
+  "third_party/externals/icu" : "https://chromium.googlesource.com/chromium/deps/icu.git@364118a1d9da24bb5b770ac3d762ac144d6da5a4",
+  "third_party/externals/dawn": "https://dawn.googlesource.com/dawn.git@22a8762fea90d2d9fbfc592d2bf2a438b66f22f4",
+}
+`
+
+func TestParseDeps_CRLF_FieldsAreClean(t *testing.T) {
+	for _, eol := range []string{"\r\n", "\r"} {
+		entries, err := ParseDeps(strings.ReplaceAll(testDEPS, "\n", eol))
+		require.NoError(t, err, "%q", eol)
+		assert.Equal(t, &deps_parser.DepsEntry{
+			Id:      "chromium.googlesource.com/chromium/deps/icu",
+			Version: "364118a1d9da24bb5b770ac3d762ac144d6da5a4",
+			Path:    "third_party/externals/icu",
+			Type:    deps_parser.DepType_Git,
+		}, entries["chromium.googlesource.com/chromium/deps/icu"], "%q", eol)
+		require.Len(t, entries, 2, "%q", eol)
+	}
+}
+
+func TestParseDeps_DictForm_EquivalentToString(t *testing.T) {
+	stringForm, err := ParseDeps(`deps = {
+  "third_party/externals/icu": "https://chromium.googlesource.com/chromium/deps/icu.git@364118a1d9da24bb5b770ac3d762ac144d6da5a4",
+}`)
+	require.NoError(t, err)
+	dictForm, err := ParseDeps(`deps = {
+  "third_party/externals/icu": {
+    "url": "https://chromium.googlesource.com/chromium/deps/icu.git@364118a1d9da24bb5b770ac3d762ac144d6da5a4",
+    "dep_type": "git",
+  },
+}`)
+	require.NoError(t, err)
+	assert.Equal(t, stringForm, dictForm)
+	assert.Equal(t, deps_parser.DepType_Git, dictForm["chromium.googlesource.com/chromium/deps/icu"].Type)
+}
+
+func TestParseDeps_Type(t *testing.T) {
+	entries, err := ParseDeps(`deps = {
+  "third_party/externals/icu": "https://chromium.googlesource.com/chromium/deps/icu.git@364118a1d9da24bb5b770ac3d762ac144d6da5a4",
+  "bin/ninja": {
+    "packages": [
+      {
+        "package": "infra/3pp/tools/ninja/${{platform}}",
+        "version": "version:2@1.12.1.chromium.4",
+      },
+    ],
+    "dep_type": "cipd",
+  },
+  "third_party/data": {
+    "dep_type": "gcs",
+    "bucket": "skia-data",
+    "objects": [
+      {
+        "object_name": "data.tar.gz",
+        "sha256sum": "0f2a6c4d8e0b1a3c5e7f9d1b3a5c7e9f1d3b5a7c9e1f3d5b7a9c1e3f5d7b9a1c",
+      },
+    ],
+  },
+}`)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, deps_parser.DepType_Git, entries["chromium.googlesource.com/chromium/deps/icu"].Type)
+	assert.Equal(t, deps_parser.DepType_Cipd, entries["infra/3pp/tools/ninja"].Type)
+	assert.Equal(t, &deps_parser.DepsEntry{
+		Id:      "skia-data/data.tar.gz",
+		Version: "0f2a6c4d8e0b1a3c5e7f9d1b3a5c7e9f1d3b5a7c9e1f3d5b7a9c1e3f5d7b9a1c",
+		Path:    "third_party/data",
+		Type:    deps_parser.DepType_Gcs,
+	}, entries["skia-data/data.tar.gz"])
+}
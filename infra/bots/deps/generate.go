@@ -9,9 +9,29 @@
 package main
 
 import (
-	"go.skia.org/infra/go/depot_tools/generator"
+	"bytes"
+	"os"
+
+	"go.skia.org/infra/go/sklog"
+	"go.skia.org/skia/infra/bots/deps/gen"
 )
 
 func main() {
-	generator.MustGenerate("../../../DEPS")
+	b, err := os.ReadFile("../../../DEPS")
+	if err != nil {
+		sklog.Fatalf("Could not read DEPS: %s", err)
+	}
+	entries, err := gen.ParseDeps(string(b))
+	if err != nil {
+		sklog.Fatalf("Could not parse DEPS: %s", err)
+	}
+	// Use gen.Generate rather than the upstream generator, which does not emit
+	// the Type of each entry.
+	var buf bytes.Buffer
+	if err := gen.Generate(&buf, entries, gen.DepsGenOptions); err != nil {
+		sklog.Fatalf("Could not generate code: %s", err)
+	}
+	if err := os.WriteFile("deps_gen.go", buf.Bytes(), 0644); err != nil {
+		sklog.Fatalf("Could not write deps_gen.go: %s", err)
+	}
 }
@@ -0,0 +1,148 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+	"go.skia.org/infra/go/skerr"
+)
+
+// GitClient provides information about commits in remote git repositories,
+// identified by their clone URLs.
+type GitClient interface {
+	// CommitTime returns the commit time of the given commit.
+	CommitTime(ctx context.Context, repo, hash string) (time.Time, error)
+	// CommitAt returns the hash of the most recent commit on the given branch
+	// which was committed at or before the given time.
+	CommitAt(ctx context.Context, repo, branch string, ts time.Time) (string, error)
+}
+
+// AncestryChecker determines the ancestry of commits in remote git
+// repositories, identified by their clone URLs.
+type AncestryChecker interface {
+	// IsAncestor returns true if the given ancestor commit is reachable from
+	// the given descendant commit.
+	IsAncestor(ctx context.Context, repo, ancestor, descendant string) (bool, error)
+}
+
+// Logger receives progress messages from long-running operations.
+type Logger interface {
+	Printf(format string, args ...any)
+}
+
+// nopLogger is the Logger used when none is provided.
+type nopLogger struct{}
+
+// Printf implements Logger.
+func (nopLogger) Printf(string, ...any) {}
+
+// loggerOrNop returns the given Logger, or a no-op Logger if it is nil.
+func loggerOrNop(logger Logger) Logger {
+	if logger == nil {
+		return nopLogger{}
+	}
+	return logger
+}
+
+// RepoURL returns the URL from which the given git dependency is cloned.
+func RepoURL(e deps_parser.DepsEntry) string {
+	return "https://" + e.Id
+}
+
+// CommitURL returns a link to the pinned commit of the given git dependency in
+// its repository's web UI. Returns false for CIPD packages and for hosts whose
+// URL scheme is not known.
+func CommitURL(e deps_parser.DepsEntry) (string, bool) {
+	if ClassifyVersion(e.Version) != VersionGit {
+		return "", false
+	}
+	host := Host(e)
+	switch {
+	case strings.HasSuffix(host, ".googlesource.com"):
+		return RepoURL(e) + "/+/" + e.Version, true
+	case host == "github.com" || host == "gitlab.com":
+		return RepoURL(e) + "/commit/" + e.Version, true
+	default:
+		return "", false
+	}
+}
+
+// CommitAges returns the commit time of the pinned version of every git
+// dependency in the given entries, keyed by ID. CIPD packages are skipped.
+func CommitAges(ctx context.Context, git GitClient, entries deps_parser.DepsEntries) (map[string]time.Time, error) {
+	rv := map[string]time.Time{}
+	for _, e := range sortedEntries(entries) {
+		if ClassifyVersion(e.Version) != VersionGit {
+			continue
+		}
+		ts, err := git.CommitTime(ctx, RepoURL(e), e.Version)
+		if err != nil {
+			return nil, skerr.Wrapf(err, "looking up commit time of %s@%s", e.Id, e.Version)
+		}
+		rv[e.Id] = ts
+	}
+	return rv, nil
+}
+
+// PinAsOf returns a copy of the given entries in which the given git dependency
+// is pinned to the most recent commit on its Branch as of the given time.
+// Returns an error for CIPD packages.
+func PinAsOf(ctx context.Context, git GitClient, entries deps_parser.DepsEntries, id string, ts time.Time) (deps_parser.DepsEntries, error) {
+	e := entries.Get(NormalizeId(id))
+	if e == nil {
+		return nil, skerr.Fmt("unknown dependency %q", id)
+	}
+	if ClassifyVersion(e.Version) != VersionGit {
+		return nil, skerr.Fmt("cannot pin %s by date; it is not a git dependency", e.Id)
+	}
+	hash, err := git.CommitAt(ctx, RepoURL(*e), Branch(*e), ts)
+	if err != nil {
+		return nil, skerr.Wrapf(err, "finding commit of %s as of %s", e.Id, ts)
+	}
+	return SetVersions(entries, map[string]string{e.Id: hash})
+}
+
+// ResolveAllLatest returns the most recent commit on the Branch of every git
+// dependency in the given entries, keyed by ID. CIPD packages are skipped. A
+// line is logged to the given Logger, which may be nil, per dependency.
+func ResolveAllLatest(ctx context.Context, git GitClient, entries deps_parser.DepsEntries, logger Logger) (map[string]string, error) {
+	logger = loggerOrNop(logger)
+	ts := time.Now()
+	rv := map[string]string{}
+	for _, e := range sortedEntries(entries) {
+		if ClassifyVersion(e.Version) != VersionGit {
+			continue
+		}
+		hash, err := git.CommitAt(ctx, RepoURL(e), Branch(e), ts)
+		if err != nil {
+			return nil, skerr.Wrapf(err, "resolving latest commit of %s", e.Id)
+		}
+		logger.Printf("Resolved %s@%s to %s", e.Id, Branch(e), hash)
+		rv[e.Id] = hash
+	}
+	return rv, nil
+}
+
+// VerifyRemote checks that the pinned version of every git dependency in the
+// given entries exists in its remote repository. CIPD packages are skipped. A
+// line is logged to the given Logger, which may be nil, per dependency.
+func VerifyRemote(ctx context.Context, git GitClient, entries deps_parser.DepsEntries, logger Logger) error {
+	logger = loggerOrNop(logger)
+	for _, e := range sortedEntries(entries) {
+		if ClassifyVersion(e.Version) != VersionGit {
+			continue
+		}
+		if _, err := git.CommitTime(ctx, RepoURL(e), e.Version); err != nil {
+			return skerr.Wrapf(err, "verifying %s@%s", e.Id, e.Version)
+		}
+		logger.Printf("Verified %s@%s", e.Id, e.Version)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+// fakeGit is a GitClient and AncestryChecker backed by canned data. times is keyed by
+// "repo@hash", commits by "repo@branch" and ancestors by
+// "repo@ancestor..descendant".
+type fakeGit struct {
+	times     map[string]time.Time
+	commits   map[string]string
+	ancestors map[string]bool
+}
+
+func (g fakeGit) CommitTime(_ context.Context, repo, hash string) (time.Time, error) {
+	ts, ok := g.times[repo+"@"+hash]
+	if !ok {
+		return time.Time{}, fmt.Errorf("unknown commit %s@%s", repo, hash)
+	}
+	return ts, nil
+}
+
+func (g fakeGit) CommitAt(_ context.Context, repo, branch string, ts time.Time) (string, error) {
+	hash, ok := g.commits[repo+"@"+branch]
+	if !ok {
+		return "", fmt.Errorf("no commits on %s@%s before %s", repo, branch, ts)
+	}
+	return hash, nil
+}
+
+func (g fakeGit) IsAncestor(_ context.Context, repo, ancestor, descendant string) (bool, error) {
+	return g.ancestors[repo+"@"+ancestor+".."+descendant], nil
+}
+
+func TestCommitAges_SkipsCIPD(t *testing.T) {
+	entries := deps_parser.DepsEntries{
+		"chromium.googlesource.com/chromium/deps/icu": deps["chromium.googlesource.com/chromium/deps/icu"],
+		"dawn.googlesource.com/dawn":                  deps["dawn.googlesource.com/dawn"],
+		"infra/3pp/tools/ninja":                       deps["infra/3pp/tools/ninja"],
+	}
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	git := fakeGit{times: map[string]time.Time{
+		"https://chromium.googlesource.com/chromium/deps/icu@364118a1d9da24bb5b770ac3d762ac144d6da5a4": t1,
+		"https://dawn.googlesource.com/dawn@22a8762fea90d2d9fbfc592d2bf2a438b66f22f4":                  t2,
+	}}
+	ages, err := CommitAges(context.Background(), git, entries)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Time{
+		"chromium.googlesource.com/chromium/deps/icu": t1,
+		"dawn.googlesource.com/dawn":                  t2,
+	}, ages)
+}
+
+func TestCommitAges_LookupFails_Error(t *testing.T) {
+	_, err := CommitAges(context.Background(), fakeGit{}, deps)
+	require.Error(t, err)
+}
+
+func TestCommitURL(t *testing.T) {
+	url, ok := CommitURL(*deps["chromium.googlesource.com/chromium/deps/icu"])
+	assert.True(t, ok)
+	assert.Equal(t, "https://chromium.googlesource.com/chromium/deps/icu/+/364118a1d9da24bb5b770ac3d762ac144d6da5a4", url)
+
+	url, ok = CommitURL(*deps["github.com/skia-dev/delaunator-cpp"])
+	assert.True(t, ok)
+	assert.Equal(t, "https://github.com/skia-dev/delaunator-cpp/commit/98305ef6c4e862f7d48df9cc647b690d796fec68", url)
+
+	url, ok = CommitURL(*deps["infra/3pp/tools/ninja"])
+	assert.False(t, ok)
+	assert.Empty(t, url)
+}
+
+func TestPinAsOf_GitEntry_Pinned(t *testing.T) {
+	const icu = "chromium.googlesource.com/chromium/deps/icu"
+	git := fakeGit{commits: map[string]string{
+		"https://" + icu + "@main": "0123456789abcdef0123456789abcdef01234567",
+	}}
+	pinned, err := PinAsOf(context.Background(), git, deps, icu, time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789abcdef0123456789abcdef01234567", pinned[icu].Version)
+	assert.NotEqual(t, pinned[icu].Version, deps[icu].Version)
+}
+
+func TestPinAsOf_CIPDEntry_Error(t *testing.T) {
+	_, err := PinAsOf(context.Background(), fakeGit{}, deps, "infra/3pp/tools/ninja", time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a git dependency")
+}
+
+// recordingLogger is a Logger which records every message.
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Printf(format string, args ...any) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+// loggerTestEntries returns two git dependencies and a CIPD package.
+func loggerTestEntries() deps_parser.DepsEntries {
+	return deps_parser.DepsEntries{
+		"chromium.googlesource.com/chromium/deps/icu": deps["chromium.googlesource.com/chromium/deps/icu"],
+		"dawn.googlesource.com/dawn":                  deps["dawn.googlesource.com/dawn"],
+		"infra/3pp/tools/ninja":                       deps["infra/3pp/tools/ninja"],
+	}
+}
+
+func TestResolveAllLatest_LogsEachEntry(t *testing.T) {
+	git := fakeGit{commits: map[string]string{
+		"https://chromium.googlesource.com/chromium/deps/icu@main": newHash,
+		"https://dawn.googlesource.com/dawn@main":                  testHash,
+	}}
+	logger := &recordingLogger{}
+	latest, err := ResolveAllLatest(context.Background(), git, loggerTestEntries(), logger)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"chromium.googlesource.com/chromium/deps/icu": newHash,
+		"dawn.googlesource.com/dawn":                  testHash,
+	}, latest)
+	assert.Equal(t, []string{
+		"Resolved chromium.googlesource.com/chromium/deps/icu@main to " + newHash,
+		"Resolved dawn.googlesource.com/dawn@main to " + testHash,
+	}, logger.lines)
+
+	// A nil Logger is allowed.
+	_, err = ResolveAllLatest(context.Background(), git, loggerTestEntries(), nil)
+	require.NoError(t, err)
+}
+
+func TestVerifyRemote_LogsEachEntry(t *testing.T) {
+	git := fakeGit{times: map[string]time.Time{
+		"https://chromium.googlesource.com/chromium/deps/icu@364118a1d9da24bb5b770ac3d762ac144d6da5a4": {},
+		"https://dawn.googlesource.com/dawn@22a8762fea90d2d9fbfc592d2bf2a438b66f22f4":                  {},
+	}}
+	logger := &recordingLogger{}
+	require.NoError(t, VerifyRemote(context.Background(), git, loggerTestEntries(), logger))
+	assert.Equal(t, []string{
+		"Verified chromium.googlesource.com/chromium/deps/icu@364118a1d9da24bb5b770ac3d762ac144d6da5a4",
+		"Verified dawn.googlesource.com/dawn@22a8762fea90d2d9fbfc592d2bf2a438b66f22f4",
+	}, logger.lines)
+
+	err := VerifyRemote(context.Background(), fakeGit{}, loggerTestEntries(), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "verifying chromium.googlesource.com/chromium/deps/icu@")
+}
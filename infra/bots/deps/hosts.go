@@ -0,0 +1,239 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+	"go.skia.org/infra/go/skerr"
+	"go.skia.org/skia/infra/bots/deps/gen"
+)
+
+// Host returns the host portion of the given entry's ID, or the empty string if
+// the ID has no host. See gen.Host.
+func Host(e deps_parser.DepsEntry) string {
+	return gen.Host(e)
+}
+
+// AffectedByHostOutage returns the dependencies, sorted by ID, which cannot be
+// synced while the given host is unavailable.
+func AffectedByHostOutage(host string) []deps_parser.DepsEntry {
+	var rv []deps_parser.DepsEntry
+	for _, e := range sortedEntries(deps) {
+		if Host(e) == host {
+			rv = append(rv, e)
+		}
+	}
+	return rv
+}
+
+// OffAllowlist returns the git dependencies, sorted by ID, whose host is not in
+// the given list of approved hosts. CIPD packages are exempt.
+func OffAllowlist(entries deps_parser.DepsEntries, allow []string) []deps_parser.DepsEntry {
+	allowed := make(map[string]bool, len(allow))
+	for _, host := range allow {
+		allowed[host] = true
+	}
+	var rv []deps_parser.DepsEntry
+	for _, e := range sortedEntries(entries) {
+		if host := Host(e); host != "" && !allowed[host] {
+			rv = append(rv, e)
+		}
+	}
+	return rv
+}
+
+// MismatchedHostScheme returns the entries, sorted by ID, whose version scheme
+// doesn't match their ID: entries with a git host which are not pinned to a
+// git hash, and host-less CIPD packages which are.
+func MismatchedHostScheme(entries deps_parser.DepsEntries) []deps_parser.DepsEntry {
+	return filterEntries(entries, func(e deps_parser.DepsEntry) bool {
+		isGit := ClassifyVersion(e.Version) == VersionGit
+		hasHost := Host(e) != ""
+		return isGit != hasHost
+	})
+}
+
+// Resolver looks up the addresses of a host. It is satisfied by *net.Resolver.
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// CheckHostsResolvable looks up every host used by the dependencies and returns
+// the sorted list of hosts which failed to resolve.
+func CheckHostsResolvable(ctx context.Context, resolver Resolver) ([]string, error) {
+	hosts := map[string]bool{}
+	for _, e := range deps {
+		if host := Host(*e); host != "" {
+			hosts[host] = true
+		}
+	}
+	var failed []string
+	for host := range hosts {
+		if err := ctx.Err(); err != nil {
+			return nil, skerr.Wrap(err)
+		}
+		if _, err := resolver.LookupHost(ctx, host); err != nil {
+			failed = append(failed, host)
+		}
+	}
+	sort.Strings(failed)
+	return failed, nil
+}
+
+// RehostAll returns a copy of the given entries in which every entry on oldHost
+// has been moved to newHost. Returns an error if the new IDs would collide.
+func RehostAll(entries deps_parser.DepsEntries, oldHost, newHost string) (deps_parser.DepsEntries, error) {
+	rv := make(deps_parser.DepsEntries, len(entries))
+	for _, e := range entries {
+		cp := *e
+		if Host(cp) == oldHost {
+			cp.Id = newHost + strings.TrimPrefix(cp.Id, oldHost)
+		}
+		if _, ok := rv[cp.Id]; ok {
+			return nil, skerr.Fmt("rehosting %s to %s results in duplicate ID %q", oldHost, newHost, cp.Id)
+		}
+		rv[cp.Id] = &cp
+	}
+	return rv, nil
+}
+
+// aliasHostPrefixes maps ID prefixes of mirrors to the host which they mirror.
+var aliasHostPrefixes = map[string]string{
+	"chromium.googlesource.com/external/github.com/": "github.com",
+	"chromium.googlesource.com/external/gitlab.com/": "gitlab.com",
+	"skia.googlesource.com/external/github.com/":     "github.com",
+}
+
+// AliasHosts returns the sorted ID prefixes which are known to be mirrors of
+// another host, eg. "skia.googlesource.com/external/github.com/".
+func AliasHosts() []string {
+	rv := make([]string, 0, len(aliasHostPrefixes))
+	for prefix := range aliasHostPrefixes {
+		rv = append(rv, prefix)
+	}
+	sort.Strings(rv)
+	return rv
+}
+
+// AliasedEntries returns the dependencies, sorted by ID, which are fetched from
+// a mirror of another host.
+func AliasedEntries() []deps_parser.DepsEntry {
+	var rv []deps_parser.DepsEntry
+	for _, e := range sortedEntries(deps) {
+		for prefix := range aliasHostPrefixes {
+			if strings.HasPrefix(e.Id, prefix) {
+				rv = append(rv, e)
+				break
+			}
+		}
+	}
+	return rv
+}
+
+// AuthRequiredHosts are hosts which require credentials to fetch from, eg.
+// private mirrors. None of the committed dependencies use such a host.
+var AuthRequiredHosts = map[string]bool{}
+
+// AuthRequiredEntries returns the dependencies, sorted by ID, whose host is
+// listed in AuthRequiredHosts.
+func AuthRequiredEntries() []deps_parser.DepsEntry {
+	return filterEntries(deps, func(e deps_parser.DepsEntry) bool {
+		return AuthRequiredHosts[Host(e)]
+	})
+}
+
+// GithubRepo returns the owner and name of the GitHub repository which the
+// given entry is fetched from, either directly or via a mirror listed in
+// aliasHostPrefixes. Returns false if the entry is not from GitHub.
+func GithubRepo(e deps_parser.DepsEntry) (string, string, bool) {
+	rest, ok := strings.CutPrefix(e.Id, "github.com/")
+	if !ok {
+		for prefix, host := range aliasHostPrefixes {
+			if host == "github.com" && strings.HasPrefix(e.Id, prefix) {
+				rest, ok = strings.TrimPrefix(e.Id, prefix), true
+				break
+			}
+		}
+	}
+	if !ok {
+		return "", "", false
+	}
+	owner, repo, ok := strings.Cut(rest, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", "", false
+	}
+	return owner, repo, true
+}
+
+// HasUpstream returns true if any dependency is fetched from the given GitHub
+// repository, either directly or via a mirror. The comparison ignores case.
+func HasUpstream(githubOwner, githubRepo string) bool {
+	for _, e := range deps {
+		if owner, repo, ok := GithubRepo(*e); ok && strings.EqualFold(owner, githubOwner) && strings.EqualFold(repo, githubRepo) {
+			return true
+		}
+	}
+	return false
+}
+
+// GithubRepoInfo describes the current state of a GitHub repository.
+type GithubRepoInfo struct {
+	// FullName is the current "owner/repo" name of the repository, which
+	// differs from the requested one if it was renamed or transferred.
+	FullName string
+	// Archived is true if the repository is read-only.
+	Archived bool
+}
+
+// GithubClient looks up GitHub repositories.
+type GithubClient interface {
+	// GetRepo returns information about the given repository, following
+	// redirects from old names.
+	GetRepo(ctx context.Context, owner, repo string) (GithubRepoInfo, error)
+}
+
+// GithubStatus describes the upstream GitHub repository of a dependency.
+type GithubStatus struct {
+	Id       string
+	Owner    string
+	Repo     string
+	Archived bool
+	// RenamedTo is the new "owner/repo" name of the repository, or empty if
+	// it was not renamed.
+	RenamedTo string
+}
+
+// CheckGithubStatus looks up the upstream repository of every dependency in the
+// given entries which is fetched from GitHub, either directly or via a mirror,
+// and returns their statuses, sorted by ID. Other dependencies are skipped.
+func CheckGithubStatus(ctx context.Context, gh GithubClient, entries deps_parser.DepsEntries) ([]GithubStatus, error) {
+	var rv []GithubStatus
+	for _, e := range sortedEntries(entries) {
+		owner, repo, ok := GithubRepo(e)
+		if !ok {
+			continue
+		}
+		info, err := gh.GetRepo(ctx, owner, repo)
+		if err != nil {
+			return nil, skerr.Wrapf(err, "looking up GitHub repo %s/%s of %s", owner, repo, e.Id)
+		}
+		status := GithubStatus{
+			Id:       e.Id,
+			Owner:    owner,
+			Repo:     repo,
+			Archived: info.Archived,
+		}
+		if !strings.EqualFold(info.FullName, owner+"/"+repo) {
+			status.RenamedTo = info.FullName
+		}
+		rv = append(rv, status)
+	}
+	return rv, nil
+}
@@ -0,0 +1,177 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+func TestHost(t *testing.T) {
+	assert.Equal(t, "dawn.googlesource.com", Host(deps_parser.DepsEntry{Id: "dawn.googlesource.com/dawn"}))
+	assert.Equal(t, "", Host(deps_parser.DepsEntry{Id: "infra/3pp/tools/ninja"}))
+}
+
+type fakeResolver struct {
+	bad string
+}
+
+func (r fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if host == r.bad {
+		return nil, errors.New("no such host")
+	}
+	return []string{"127.0.0.1"}, nil
+}
+
+func TestCheckHostsResolvable_OneHostFails_ReturnsIt(t *testing.T) {
+	failed, err := CheckHostsResolvable(context.Background(), fakeResolver{bad: "swiftshader.googlesource.com"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"swiftshader.googlesource.com"}, failed)
+}
+
+func TestRehostAll_ChromiumToMirror_AllIdsUpdated(t *testing.T) {
+	rehosted, err := RehostAll(deps, "chromium.googlesource.com", "mirror.example.com")
+	require.NoError(t, err)
+	require.Len(t, rehosted, len(deps))
+	for id, e := range rehosted {
+		assert.Equal(t, id, e.Id)
+		assert.NotEqual(t, "chromium.googlesource.com", Host(*e))
+	}
+	icu := rehosted["mirror.example.com/chromium/deps/icu"]
+	require.NotNil(t, icu)
+	assert.Equal(t, deps["chromium.googlesource.com/chromium/deps/icu"].Version, icu.Version)
+	// The input is not modified.
+	assert.NotNil(t, deps["chromium.googlesource.com/chromium/deps/icu"])
+	assert.Equal(t, "chromium.googlesource.com/chromium/deps/icu", deps["chromium.googlesource.com/chromium/deps/icu"].Id)
+}
+
+func TestRehostAll_Collision_Error(t *testing.T) {
+	entries := deps_parser.DepsEntries{
+		"a.googlesource.com/x": {Id: "a.googlesource.com/x"},
+		"b.googlesource.com/x": {Id: "b.googlesource.com/x"},
+	}
+	_, err := RehostAll(entries, "a.googlesource.com", "b.googlesource.com")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `duplicate ID "b.googlesource.com/x"`)
+}
+
+func TestAliasedEntries(t *testing.T) {
+	assert.Contains(t, AliasHosts(), "skia.googlesource.com/external/github.com/")
+	aliased := map[string]bool{}
+	for _, e := range AliasedEntries() {
+		aliased[e.Id] = true
+	}
+	assert.True(t, aliased["skia.googlesource.com/external/github.com/google/brotli"])
+	assert.True(t, aliased["skia.googlesource.com/external/github.com/linebender/vello"])
+	assert.False(t, aliased["chromium.googlesource.com/chromium/deps/icu"])
+}
+
+func TestAuthRequiredEntries(t *testing.T) {
+	assert.Empty(t, AuthRequiredEntries())
+
+	old := AuthRequiredHosts
+	defer func() { AuthRequiredHosts = old }()
+	AuthRequiredHosts = map[string]bool{"dawn.googlesource.com": true}
+	entries := AuthRequiredEntries()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "dawn.googlesource.com/dawn", entries[0].Id)
+}
+
+func TestAffectedByHostOutage(t *testing.T) {
+	affected := AffectedByHostOutage("dawn.googlesource.com")
+	require.Len(t, affected, 1)
+	assert.Equal(t, "dawn.googlesource.com/dawn", affected[0].Id)
+
+	assert.Empty(t, AffectedByHostOutage("example.com"))
+}
+
+func TestOffAllowlist_MissingDawn_DawnFlagged(t *testing.T) {
+	off := OffAllowlist(deps, []string{
+		"android.googlesource.com",
+		"chromium.googlesource.com",
+		"github.com",
+		"skia.googlesource.com",
+		"swiftshader.googlesource.com",
+	})
+	require.Len(t, off, 1)
+	assert.Equal(t, "dawn.googlesource.com/dawn", off[0].Id)
+}
+
+func TestMismatchedHostScheme(t *testing.T) {
+	assert.Empty(t, MismatchedHostScheme(deps))
+
+	mismatched := MismatchedHostScheme(deps_parser.DepsEntries{
+		"chromium.googlesource.com/a": {Id: "chromium.googlesource.com/a", Version: "version:2@1.0"},
+		"chromium.googlesource.com/b": {Id: "chromium.googlesource.com/b", Version: "364118a1d9da24bb5b770ac3d762ac144d6da5a4"},
+		"infra/tools/c":               {Id: "infra/tools/c", Version: "364118a1d9da24bb5b770ac3d762ac144d6da5a4"},
+		"infra/tools/d":               {Id: "infra/tools/d", Version: "version:2@1.0"},
+	})
+	require.Len(t, mismatched, 2)
+	assert.Equal(t, "chromium.googlesource.com/a", mismatched[0].Id)
+	assert.Equal(t, "infra/tools/c", mismatched[1].Id)
+}
+
+func TestGithubRepo(t *testing.T) {
+	test := func(id, expectOwner, expectRepo string, expectOk bool) {
+		t.Run(id, func(t *testing.T) {
+			owner, repo, ok := GithubRepo(deps_parser.DepsEntry{Id: id})
+			assert.Equal(t, expectOk, ok)
+			assert.Equal(t, expectOwner, owner)
+			assert.Equal(t, expectRepo, repo)
+		})
+	}
+	test("github.com/skia-dev/delaunator-cpp", "skia-dev", "delaunator-cpp", true)
+	test("skia.googlesource.com/external/github.com/google/brotli", "google", "brotli", true)
+	test("chromium.googlesource.com/external/github.com/harfbuzz/harfbuzz", "harfbuzz", "harfbuzz", true)
+	test("chromium.googlesource.com/external/gitlab.com/wg1/jpeg-xl", "", "", false)
+	test("chromium.googlesource.com/chromium/deps/icu", "", "", false)
+}
+
+func TestHasUpstream(t *testing.T) {
+	assert.True(t, HasUpstream("google", "brotli"))
+	assert.True(t, HasUpstream("KhronosGroup", "spirv-tools"))
+	assert.False(t, HasUpstream("google", "not-a-real-repo"))
+}
+
+// fakeGithub is a GithubClient backed by canned data keyed by "owner/repo".
+type fakeGithub map[string]GithubRepoInfo
+
+func (g fakeGithub) GetRepo(_ context.Context, owner, repo string) (GithubRepoInfo, error) {
+	info, ok := g[owner+"/"+repo]
+	if !ok {
+		return GithubRepoInfo{}, errors.New("not found")
+	}
+	return info, nil
+}
+
+func TestCheckGithubStatus(t *testing.T) {
+	entries := deps_parser.DepsEntries{
+		"chromium.googlesource.com/chromium/deps/icu":                     deps["chromium.googlesource.com/chromium/deps/icu"],
+		"chromium.googlesource.com/external/github.com/harfbuzz/harfbuzz": deps["chromium.googlesource.com/external/github.com/harfbuzz/harfbuzz"],
+		"github.com/example/old":                                          {Id: "github.com/example/old", Version: testHash, Path: "third_party/externals/old"},
+		"skia.googlesource.com/external/github.com/google/brotli":         deps["skia.googlesource.com/external/github.com/google/brotli"],
+	}
+	gh := fakeGithub{
+		"harfbuzz/harfbuzz": {FullName: "harfbuzz/harfbuzz"},
+		"example/old":       {FullName: "example/new"},
+		"google/brotli":     {FullName: "google/brotli", Archived: true},
+	}
+	statuses, err := CheckGithubStatus(context.Background(), gh, entries)
+	require.NoError(t, err)
+	assert.Equal(t, []GithubStatus{
+		{Id: "chromium.googlesource.com/external/github.com/harfbuzz/harfbuzz", Owner: "harfbuzz", Repo: "harfbuzz"},
+		{Id: "github.com/example/old", Owner: "example", Repo: "old", RenamedTo: "example/new"},
+		{Id: "skia.googlesource.com/external/github.com/google/brotli", Owner: "google", Repo: "brotli", Archived: true},
+	}, statuses)
+
+	_, err = CheckGithubStatus(context.Background(), fakeGithub{}, entries)
+	require.Error(t, err)
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"path"
+	"strings"
+
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+// PathNameAliases maps the lowercased repo names of git dependencies to the
+// checkout directory names which are knowingly used for them instead.
+var PathNameAliases = map[string]string{
+	"angle":                  "angle2",
+	"buildbot":               "skia-infra",
+	"d3d12memoryallocator":   "d3d12allocator",
+	"freetype2":              "freetype",
+	"jpeg-xl":                "libjxl",
+	"libexpat":               "expat",
+	"libjpeg_turbo":          "libjpeg-turbo",
+	"libmicrohttpd":          "microhttpd",
+	"partition_allocator":    "partition_alloc",
+	"wuffs-mirror-release-c": "wuffs",
+}
+
+// PathNameMismatches returns the git dependencies, sorted by ID, whose Path
+// basename differs from the repo name at the end of their ID, ignoring case
+// and the renames listed in PathNameAliases. CIPD packages are ignored, since
+// their Paths are installation directories.
+func PathNameMismatches(entries deps_parser.DepsEntries) []deps_parser.DepsEntry {
+	var rv []deps_parser.DepsEntry
+	for _, e := range sortedEntries(entries) {
+		if ClassifyVersion(e.Version) != VersionGit {
+			continue
+		}
+		repoName := strings.ToLower(path.Base(e.Id))
+		pathName := strings.ToLower(path.Base(e.Path))
+		if repoName != pathName && PathNameAliases[repoName] != pathName {
+			rv = append(rv, e)
+		}
+	}
+	return rv
+}
+
+// RenamedOnCheckout returns the git dependencies, sorted by ID, which are
+// checked out under a different name than their repo, ie. whose Path basename
+// differs from the basename of their ID, ignoring case, eg. libjpeg_turbo in
+// "third_party/externals/libjpeg-turbo". Unlike PathNameMismatches, renames
+// listed in PathNameAliases are included, for documentation purposes.
+func RenamedOnCheckout(entries deps_parser.DepsEntries) []deps_parser.DepsEntry {
+	return filterEntries(entries, func(e deps_parser.DepsEntry) bool {
+		if ClassifyVersion(e.Version) != VersionGit {
+			return false
+		}
+		return !strings.EqualFold(path.Base(NormalizeId(e.Id)), path.Base(path.Clean(e.Path)))
+	})
+}
+
+// IsMonorepoSubpath returns true if the given entry's ID names a directory
+// inside a larger repository, by way of a "/src/" segment after the repository
+// path, eg. "chromium.googlesource.com/chromium/src/third_party/zlib".
+func IsMonorepoSubpath(e deps_parser.DepsEntry) bool {
+	if Host(e) == "" {
+		return false
+	}
+	_, subpath, ok := strings.Cut(e.Id, "/src/")
+	return ok && subpath != ""
+}
+
+// MonorepoSubpaths returns the dependencies, sorted by ID, which are
+// IsMonorepoSubpath.
+func MonorepoSubpaths() []deps_parser.DepsEntry {
+	return filterEntries(deps, IsMonorepoSubpath)
+}
+
+// Slug returns a URL-safe anchor for the given entry, derived from its Path by
+// lowercasing and replacing runs of other characters with "-". CIPD packages
+// also include the package name, since several may share a Path.
+func Slug(e deps_parser.DepsEntry) string {
+	name := e.Path
+	if ClassifyVersion(e.Version) == VersionCIPD {
+		name += "/" + path.Base(e.Id)
+	}
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+const testHash = "364118a1d9da24bb5b770ac3d762ac144d6da5a4"
+
+func TestPathNameMismatches_CommittedEntries_AllAliased(t *testing.T) {
+	assert.Empty(t, PathNameMismatches(deps))
+}
+
+func TestPathNameMismatches_GenuineMismatch_Flagged(t *testing.T) {
+	entries := deps_parser.DepsEntries{
+		"chromium.googlesource.com/angle/angle": {Id: "chromium.googlesource.com/angle/angle", Version: testHash, Path: "third_party/externals/angle2"},
+		"github.com/google/Brotli":              {Id: "github.com/google/Brotli", Version: testHash, Path: "third_party/externals/brotli"},
+		"github.com/google/highway":             {Id: "github.com/google/highway", Version: testHash, Path: "third_party/externals/harfbuzz"},
+	}
+	mismatches := PathNameMismatches(entries)
+	require.Len(t, mismatches, 1)
+	assert.Equal(t, "github.com/google/highway", mismatches[0].Id)
+}
+
+func TestRenamedOnCheckout(t *testing.T) {
+	renamed := map[string]string{}
+	for _, e := range RenamedOnCheckout(deps) {
+		renamed[e.Id] = e.Path
+	}
+	assert.Equal(t, "third_party/externals/libjpeg-turbo", renamed["chromium.googlesource.com/chromium/deps/libjpeg_turbo"])
+	assert.NotContains(t, renamed, "chromium.googlesource.com/chromium/deps/icu")
+	assert.NotContains(t, renamed, "skia/tools/sk", "CIPD packages are ignored")
+	assert.Len(t, renamed, len(PathNameAliases))
+}
+
+func TestMonorepoSubpaths(t *testing.T) {
+	subpaths := map[string]bool{}
+	for _, e := range MonorepoSubpaths() {
+		assert.True(t, IsMonorepoSubpath(e))
+		subpaths[e.Id] = true
+	}
+	for _, name := range []string{"freetype2", "jinja2", "markupsafe", "zlib"} {
+		assert.True(t, subpaths["chromium.googlesource.com/chromium/src/third_party/"+name], name)
+	}
+	assert.False(t, subpaths["chromium.googlesource.com/chromium/deps/icu"])
+	assert.False(t, subpaths["dawn.googlesource.com/dawn"])
+	assert.False(t, IsMonorepoSubpath(deps_parser.DepsEntry{Id: "example.googlesource.com/src/"}))
+	assert.False(t, IsMonorepoSubpath(deps_parser.DepsEntry{Id: "infra/src/tool"}))
+}
+
+func TestSlug(t *testing.T) {
+	assert.Equal(t, "third-party-externals-libjpeg-turbo", Slug(*deps["chromium.googlesource.com/chromium/deps/libjpeg_turbo"]))
+	assert.Equal(t, "bin-ninja", Slug(*deps["infra/3pp/tools/ninja"]))
+	assert.Equal(t, "bin-sk", Slug(*deps["skia/tools/sk"]))
+	assert.Equal(t, "a-b-c", Slug(deps_parser.DepsEntry{Path: "/A__b/./c/"}))
+}
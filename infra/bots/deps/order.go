@@ -0,0 +1,104 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"sort"
+	"strings"
+
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+// SortKey returns a key which orders entries by Path and then by ID. Because
+// the separator sorts before any other character, a dependency sorts before any
+// dependency nested inside its Path.
+func SortKey(e deps_parser.DepsEntry) string {
+	return e.Path + "\x00" + e.Id
+}
+
+// SyncOrder returns the given entries in the order in which they should be
+// synced, such that every dependency comes before those nested inside it.
+func SyncOrder(entries deps_parser.DepsEntries) []deps_parser.DepsEntry {
+	rv := sortedEntries(entries)
+	sort.SliceStable(rv, func(i, j int) bool {
+		return SortKey(rv[i]) < SortKey(rv[j])
+	})
+	return rv
+}
+
+// PathBounds returns the dependencies which sort first and last by SortKey, ie.
+// by Path and then by ID. Returns zero-valued entries if there are none.
+func PathBounds() (first, last deps_parser.DepsEntry) {
+	sorted := SyncOrder(deps)
+	if len(sorted) == 0 {
+		return first, last
+	}
+	return sorted[0], sorted[len(sorted)-1]
+}
+
+// SortByVersionLen returns the dependencies sorted by the length of their
+// Version, longest first, and then by ID.
+func SortByVersionLen() []deps_parser.DepsEntry {
+	rv := sortedEntries(deps)
+	sort.SliceStable(rv, func(i, j int) bool {
+		return len(rv[i].Version) > len(rv[j].Version)
+	})
+	return rv
+}
+
+// HumanSorted returns the dependencies sorted by Path and then ID, comparing
+// runs of digits numerically so that eg. "lib2" sorts before "lib10".
+func HumanSorted() []deps_parser.DepsEntry {
+	rv := sortedEntries(deps)
+	sort.SliceStable(rv, func(i, j int) bool {
+		if rv[i].Path != rv[j].Path {
+			return naturalLess(rv[i].Path, rv[j].Path)
+		}
+		return naturalLess(rv[i].Id, rv[j].Id)
+	})
+	return rv
+}
+
+// naturalLess compares the given strings, treating runs of digits as numbers.
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			numA, restA := splitDigits(a)
+			numB, restB := splitDigits(b)
+			trimmedA, trimmedB := strings.TrimLeft(numA, "0"), strings.TrimLeft(numB, "0")
+			if len(trimmedA) != len(trimmedB) {
+				return len(trimmedA) < len(trimmedB)
+			}
+			if trimmedA != trimmedB {
+				return trimmedA < trimmedB
+			}
+			if numA != numB {
+				// Equal values; fewer leading zeroes first.
+				return len(numA) < len(numB)
+			}
+			a, b = restA, restB
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return a == "" && b != ""
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// splitDigits splits the leading run of digits from s.
+func splitDigits(s string) (string, string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+func TestSortKey_MatchesSyncOrder(t *testing.T) {
+	entries := deps_parser.DepsEntries{
+		"z/parent":  {Id: "z/parent", Path: "third_party/a"},
+		"a/child":   {Id: "a/child", Path: "third_party/a/b"},
+		"b/sibling": {Id: "b/sibling", Path: "third_party/a-b"},
+		"y/bin":     {Id: "y/bin", Path: "bin"},
+		"x/bin":     {Id: "x/bin", Path: "bin"},
+	}
+	var ids []string
+	for _, e := range SyncOrder(entries) {
+		ids = append(ids, e.Id)
+	}
+	assert.Equal(t, []string{"x/bin", "y/bin", "z/parent", "b/sibling", "a/child"}, ids)
+
+	assert.Less(t, SortKey(*entries["x/bin"]), SortKey(*entries["y/bin"]))
+	assert.Less(t, SortKey(*entries["z/parent"]), SortKey(*entries["a/child"]))
+}
+
+func TestPathBounds(t *testing.T) {
+	first, last := PathBounds()
+	assert.Equal(t, "bin", first.Path)
+	assert.Equal(t, "infra/3pp/tools/ninja", first.Id, "ties are broken by ID")
+	assert.Equal(t, "third_party/externals/zlib", last.Path)
+	for _, e := range deps {
+		assert.LessOrEqual(t, first.Path, e.Path)
+		assert.GreaterOrEqual(t, last.Path, e.Path)
+	}
+}
+
+func TestSortByVersionLen(t *testing.T) {
+	sorted := SortByVersionLen()
+	require.Len(t, sorted, len(deps))
+	// The "git_revision:" CIPD tags are longer than git hashes, but the
+	// "version:" tag of ninja is shorter.
+	assert.Equal(t, "skia/tools/bazel_build", sorted[0].Id)
+	assert.Equal(t, "skia/tools/sk", sorted[1].Id)
+	assert.Len(t, sorted[2].Version, 40)
+	assert.Equal(t, "infra/3pp/tools/ninja", sorted[len(sorted)-1].Id)
+	for i := 1; i < len(sorted); i++ {
+		prev, cur := sorted[i-1], sorted[i]
+		assert.True(t, len(prev.Version) > len(cur.Version) || (len(prev.Version) == len(cur.Version) && prev.Id < cur.Id))
+	}
+}
+
+func TestNaturalLess(t *testing.T) {
+	assert.True(t, naturalLess("angle", "angle2"))
+	assert.False(t, naturalLess("angle2", "angle"))
+	assert.True(t, naturalLess("lib2", "lib10"))
+	assert.False(t, naturalLess("lib10", "lib2"))
+	assert.True(t, naturalLess("v1.9", "v1.10"))
+	assert.True(t, naturalLess("a2b", "a2c"))
+	assert.True(t, naturalLess("x01", "x001"))
+	assert.False(t, naturalLess("same", "same"))
+}
+
+func TestHumanSorted(t *testing.T) {
+	sorted := HumanSorted()
+	require.Len(t, sorted, len(deps))
+	for i := 1; i < len(sorted); i++ {
+		assert.False(t, naturalLess(sorted[i].Path, sorted[i-1].Path), "%s before %s", sorted[i-1].Path, sorted[i].Path)
+	}
+	assert.Equal(t, "bin", sorted[0].Path)
+}
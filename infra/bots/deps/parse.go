@@ -0,0 +1,43 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"io/fs"
+
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+	"go.skia.org/infra/go/skerr"
+	"go.skia.org/skia/infra/bots/deps/gen"
+)
+
+// ParseDeps parses the given DEPS file contents. See gen.ParseDeps.
+func ParseDeps(contents string) (deps_parser.DepsEntries, error) {
+	return gen.ParseDeps(contents)
+}
+
+// ParseDepsFS reads the named DEPS file from the given filesystem, eg. an
+// embed.FS, and parses it using ParseDeps.
+func ParseDepsFS(fsys fs.FS, name string) (deps_parser.DepsEntries, error) {
+	contents, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, skerr.Wrap(err)
+	}
+	entries, err := ParseDeps(string(contents))
+	if err != nil {
+		return nil, skerr.Wrapf(err, "parsing %s", name)
+	}
+	return entries, nil
+}
+
+// CIPDPackage returns the CIPD package path of the given entry, ie. its ID
+// with any platform suffix like "/${{platform}}" already removed by parsing.
+// Returns false if the entry is not a CIPD package.
+func CIPDPackage(e deps_parser.DepsEntry) (string, bool) {
+	if e.Type != deps_parser.DepType_Cipd {
+		return "", false
+	}
+	return e.Id, true
+}
@@ -0,0 +1,54 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+const testDEPS = `use_relative_paths = True
+
+deps = {
+  "third_party/externals/icu" : "https://chromium.googlesource.com/chromium/deps/icu.git@364118a1d9da24bb5b770ac3d762ac144d6da5a4",
+  "third_party/externals/dawn": "https://dawn.googlesource.com/dawn.git@22a8762fea90d2d9fbfc592d2bf2a438b66f22f4",
+}
+`
+
+func TestType_CommittedEntries(t *testing.T) {
+	assert.Equal(t, deps_parser.DepType_Git, deps["skia.googlesource.com/buildbot"].Type)
+	assert.Equal(t, deps_parser.DepType_Cipd, deps["infra/3pp/tools/ninja"].Type)
+}
+
+func TestParseDepsFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"testdata/DEPS": &fstest.MapFile{Data: []byte(testDEPS)},
+	}
+	entries, err := ParseDepsFS(fsys, "testdata/DEPS")
+	require.NoError(t, err)
+	expect, err := ParseDeps(testDEPS)
+	require.NoError(t, err)
+	assert.Equal(t, expect, entries)
+
+	_, err = ParseDepsFS(fsys, "DEPS")
+	require.Error(t, err)
+}
+
+func TestCIPDPackage(t *testing.T) {
+	pkg, ok := CIPDPackage(*deps["infra/3pp/tools/ninja"])
+	require.True(t, ok)
+	assert.Equal(t, "infra/3pp/tools/ninja", pkg)
+
+	_, ok = CIPDPackage(*deps["dawn.googlesource.com/dawn"])
+	assert.False(t, ok)
+
+	_, ok = CIPDPackage(deps_parser.DepsEntry{Id: "skia-data/data.tar.gz", Type: deps_parser.DepType_Gcs})
+	assert.False(t, ok)
+}
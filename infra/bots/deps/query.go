@@ -0,0 +1,90 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+// minAbbrevHashLen is the shortest abbreviated git hash which will be matched
+// against full hashes.
+const minAbbrevHashLen = 7
+
+// MatchBlocklist returns the entries, sorted by ID, which are pinned to any of
+// the given bad versions. Git hashes in the blocklist may be abbreviated, and
+// also match CIPD packages built at that revision, ie. "git_revision:<hash>".
+func MatchBlocklist(entries deps_parser.DepsEntries, bad []string) []deps_parser.DepsEntry {
+	var rv []deps_parser.DepsEntry
+	for _, e := range sortedEntries(entries) {
+		for _, b := range bad {
+			if versionMatches(e.Version, b) {
+				rv = append(rv, e)
+				break
+			}
+		}
+	}
+	return rv
+}
+
+// versionMatches returns true if the given version is the same as pattern, or
+// if version is a git hash and pattern is an abbreviation of it.
+func versionMatches(version, pattern string) bool {
+	if version == pattern {
+		return true
+	}
+	version = strings.TrimPrefix(version, gitRevisionPrefix)
+	if ClassifyVersion(version) != VersionGit || len(pattern) < minAbbrevHashLen {
+		return false
+	}
+	return strings.HasPrefix(strings.ToLower(version), strings.ToLower(pattern))
+}
+
+// PathsMatching returns the entries, sorted by ID, whose Path matches any of the
+// given glob patterns, eg. "third_party/externals/*". Patterns use the syntax
+// of path.Match; malformed patterns match nothing.
+func PathsMatching(entries deps_parser.DepsEntries, patterns []string) []deps_parser.DepsEntry {
+	var rv []deps_parser.DepsEntry
+	for _, e := range sortedEntries(entries) {
+		for _, pattern := range patterns {
+			if ok, err := path.Match(pattern, e.Path); err == nil && ok {
+				rv = append(rv, e)
+				break
+			}
+		}
+	}
+	return rv
+}
+
+// IndexByInitial returns every dependency keyed by the first rune of its ID,
+// with each bucket sorted by ID.
+func IndexByInitial() map[rune][]deps_parser.DepsEntry {
+	rv := map[rune][]deps_parser.DepsEntry{}
+	for _, e := range sortedEntries(deps) {
+		initial, _ := utf8.DecodeRuneInString(e.Id)
+		rv[initial] = append(rv[initial], e)
+	}
+	return rv
+}
+
+// GroupBySecondSegment returns every dependency, with each group sorted by ID,
+// keyed by the second segment of its Path, eg. "externals" for
+// "third_party/externals/icu". Dependencies whose Path has a single segment,
+// eg. "bin", are keyed by the empty string.
+func GroupBySecondSegment() map[string][]deps_parser.DepsEntry {
+	rv := map[string][]deps_parser.DepsEntry{}
+	for _, e := range sortedEntries(deps) {
+		segment := ""
+		if segments := strings.Split(e.Path, "/"); len(segments) > 1 {
+			segment = segments[1]
+		}
+		rv[segment] = append(rv[segment], e)
+	}
+	return rv
+}
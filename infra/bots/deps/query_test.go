@@ -0,0 +1,99 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchBlocklist(t *testing.T) {
+	test := func(name string, bad []string, expectIds ...string) {
+		t.Run(name, func(t *testing.T) {
+			var ids []string
+			for _, e := range MatchBlocklist(deps, bad) {
+				ids = append(ids, e.Id)
+			}
+			assert.Equal(t, expectIds, ids)
+		})
+	}
+	test("full hash", []string{"364118a1d9da24bb5b770ac3d762ac144d6da5a4"}, "chromium.googlesource.com/chromium/deps/icu")
+	test("abbreviated hash", []string{"364118a"}, "chromium.googlesource.com/chromium/deps/icu")
+	test("too short", []string{"3641"})
+	test("cipd", []string{"version:2@1.12.1.chromium.4"}, "infra/3pp/tools/ninja")
+	test("no match", []string{"0123456789abcdef0123456789abcdef01234567"})
+}
+
+func TestMatchBlocklist_GitRevisionCIPD_Matches(t *testing.T) {
+	bad := MatchBlocklist(deps, []string{"ca6066d7097c"})
+	require.Len(t, bad, 2)
+	assert.Equal(t, "skia.googlesource.com/buildbot", bad[0].Id)
+	assert.Equal(t, "skia/tools/sk", bad[1].Id)
+}
+
+func TestPathsMatching_ExternalsGlob_ReturnsAllExternals(t *testing.T) {
+	matched := PathsMatching(deps, []string{"third_party/externals/*"})
+	expect := 0
+	for _, e := range deps {
+		if strings.HasPrefix(e.Path, "third_party/externals/") {
+			expect++
+		}
+	}
+	assert.Len(t, matched, expect)
+	for _, e := range matched {
+		assert.True(t, strings.HasPrefix(e.Path, "third_party/externals/"), e.Path)
+	}
+}
+
+func TestPathsMatching_MultiplePatterns(t *testing.T) {
+	matched := PathsMatching(deps, []string{"bin", "buildtools", "[invalid"})
+	var ids []string
+	for _, e := range matched {
+		ids = append(ids, e.Id)
+	}
+	assert.Equal(t, []string{"chromium.googlesource.com/chromium/src/buildtools", "infra/3pp/tools/ninja", "skia/tools/sk"}, ids)
+}
+
+func TestIndexByInitial(t *testing.T) {
+	index := IndexByInitial()
+	for _, initial := range []rune{'a', 'c', 's'} {
+		bucket := index[initial]
+		require.NotEmpty(t, bucket, string(initial))
+		for i, e := range bucket {
+			assert.Equal(t, initial, rune(e.Id[0]))
+			if i > 0 {
+				assert.Less(t, bucket[i-1].Id, e.Id)
+			}
+		}
+	}
+	assert.Equal(t, "dawn.googlesource.com/dawn", index['d'][0].Id)
+
+	total := 0
+	for _, bucket := range index {
+		total += len(bucket)
+	}
+	assert.Equal(t, len(deps), total)
+}
+
+func TestGroupBySecondSegment(t *testing.T) {
+	groups := GroupBySecondSegment()
+	for segment, group := range groups {
+		assert.LessOrEqual(t, len(group), len(groups["externals"]), segment)
+	}
+	assert.Greater(t, len(groups["externals"]), len(deps)/2)
+
+	var topLevel []string
+	for _, e := range groups[""] {
+		topLevel = append(topLevel, e.Path)
+	}
+	assert.Contains(t, topLevel, "bin")
+	assert.Contains(t, topLevel, "buildtools")
+	assert.NotContains(t, topLevel, "infra/skia-infra")
+	assert.Len(t, groups["skia-infra"], 1)
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+	"go.skia.org/infra/go/skerr"
+)
+
+// renameFile is used by SaveSnapshot to move the finished snapshot into place.
+// It is a variable so that tests can simulate failures.
+var renameFile = os.Rename
+
+// SaveSnapshot writes the given entries to the given path as JSON. The snapshot
+// is written to a temporary file in the same directory and then renamed into
+// place, so an existing snapshot is never left partially written.
+func SaveSnapshot(path string, entries deps_parser.DepsEntries) error {
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return skerr.Wrap(err)
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return skerr.Wrap(err)
+	}
+	tmp := f.Name()
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmp)
+		return skerr.Wrapf(err, "writing %s", tmp)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmp)
+		return skerr.Wrapf(err, "syncing %s", tmp)
+	}
+	// os.CreateTemp creates files readable only by their owner.
+	if err := f.Chmod(0644); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmp)
+		return skerr.Wrapf(err, "setting permissions of %s", tmp)
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return skerr.Wrapf(err, "closing %s", tmp)
+	}
+	if err := renameFile(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return skerr.Wrapf(err, "renaming %s to %s", tmp, path)
+	}
+	return nil
+}
+
+// LoadSnapshot reads entries previously written by SaveSnapshot.
+func LoadSnapshot(path string) (deps_parser.DepsEntries, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, skerr.Wrap(err)
+	}
+	var entries deps_parser.DepsEntries
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, skerr.Wrapf(err, "parsing snapshot %s", path)
+	}
+	return entries, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveSnapshot_LoadSnapshot_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deps.json")
+	require.NoError(t, SaveSnapshot(path, deps))
+
+	loaded, err := LoadSnapshot(path)
+	require.NoError(t, err)
+	assert.Equal(t, deps, loaded)
+}
+
+func TestSaveSnapshot_WorldReadable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deps.json")
+	require.NoError(t, SaveSnapshot(path, deps))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+}
+
+func TestSaveSnapshot_RenameFails_ExistingFileUntouched(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "deps.json")
+	require.NoError(t, os.WriteFile(path, []byte("previous"), 0644))
+
+	renameFile = func(string, string) error {
+		return errors.New("disk on fire")
+	}
+	defer func() { renameFile = os.Rename }()
+	require.Error(t, SaveSnapshot(path, deps))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "previous", string(b))
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1, "temporary file should be cleaned up")
+}
@@ -0,0 +1,149 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+// Summary returns the number of dependencies using each version scheme.
+func Summary() map[VersionKind]int {
+	rv := map[VersionKind]int{}
+	for _, e := range deps {
+		rv[ClassifyVersion(e.Version)]++
+	}
+	return rv
+}
+
+// SchemePercentages returns the percentage of dependencies using each version
+// scheme, rounded to two decimal places.
+func SchemePercentages() map[VersionKind]float64 {
+	summary := Summary()
+	total := 0
+	for _, count := range summary {
+		total += count
+	}
+	rv := make(map[VersionKind]float64, len(summary))
+	for kind, count := range summary {
+		rv[kind] = math.Round(float64(count)*10000/float64(total)) / 100
+	}
+	return rv
+}
+
+// OneLineSummary returns a short description of the dependencies suitable for
+// logging, eg. "deps: 48 entries (45 git, 3 cipd) across 5 hosts".
+func OneLineSummary() string {
+	summary := Summary()
+	hosts := map[string]bool{}
+	for _, e := range deps {
+		if host := Host(*e); host != "" {
+			hosts[host] = true
+		}
+	}
+	unknown := ""
+	if n := summary[VersionUnknown]; n > 0 {
+		unknown = fmt.Sprintf(", %d unknown", n)
+	}
+	return fmt.Sprintf("deps: %d entries (%d git, %d cipd%s) across %d hosts", len(deps), summary[VersionGit], summary[VersionCIPD], unknown, len(hosts))
+}
+
+// PathDepth returns the number of segments in the given entry's Path, eg. 3 for
+// "third_party/externals/icu".
+func PathDepth(e deps_parser.DepsEntry) int {
+	depth := 0
+	for _, segment := range strings.Split(e.Path, "/") {
+		if segment != "" {
+			depth++
+		}
+	}
+	return depth
+}
+
+// TotalPathSegments returns the sum of PathDepth over all dependencies, as a
+// rough measure of the complexity of the checkout.
+func TotalPathSegments() int {
+	return totalPathSegments(deps)
+}
+
+func totalPathSegments(entries deps_parser.DepsEntries) int {
+	total := 0
+	for _, e := range entries {
+		total += PathDepth(*e)
+	}
+	return total
+}
+
+// DistinctCIPDEpochs returns the sorted, unique epochs of the CIPD packages
+// pinned to versions of the form "version:<epoch>@<tag>".
+func DistinctCIPDEpochs() []int {
+	return distinctCIPDEpochs(deps)
+}
+
+func distinctCIPDEpochs(entries deps_parser.DepsEntries) []int {
+	seen := map[int]bool{}
+	rv := []int{}
+	for _, e := range entries {
+		epoch, _, err := parseCIPDVersionTag(e.Version)
+		if err != nil || seen[epoch] {
+			continue
+		}
+		seen[epoch] = true
+		rv = append(rv, epoch)
+	}
+	sort.Ints(rv)
+	return rv
+}
+
+// VersionLengthExtremes returns the dependencies with the longest and shortest
+// Version strings, preferring the first by ID in case of a tie.
+func VersionLengthExtremes() (longest, shortest deps_parser.DepsEntry) {
+	return versionLengthExtremes(deps)
+}
+
+func versionLengthExtremes(entries deps_parser.DepsEntries) (longest, shortest deps_parser.DepsEntry) {
+	for i, e := range sortedEntries(entries) {
+		if i == 0 || len(e.Version) > len(longest.Version) {
+			longest = e
+		}
+		if i == 0 || len(e.Version) < len(shortest.Version) {
+			shortest = e
+		}
+	}
+	return longest, shortest
+}
+
+// cipdInstanceIdRegex matches CIPD instance IDs, which identify a single,
+// immutable package instance by its hash, unlike tags such as "version:...".
+var cipdInstanceIdRegex = regexp.MustCompile(`^[A-Za-z0-9_-]{44}$`)
+
+// ImmutablePinRatio returns the fraction of dependencies which are pinned to
+// content which cannot change: git dependencies pinned to a commit hash rather
+// than a ref, and CIPD packages pinned to an instance ID rather than a tag.
+// Returns 0 if there are no dependencies.
+func ImmutablePinRatio() float64 {
+	return immutablePinRatio(deps)
+}
+
+func immutablePinRatio(entries deps_parser.DepsEntries) float64 {
+	if len(entries) == 0 {
+		return 0
+	}
+	immutable := 0
+	for _, e := range entries {
+		if e.Type == deps_parser.DepType_Git && ClassifyVersion(e.Version) == VersionGit {
+			immutable++
+		} else if e.Type == deps_parser.DepType_Cipd && cipdInstanceIdRegex.MatchString(e.Version) {
+			immutable++
+		}
+	}
+	return float64(immutable) / float64(len(entries))
+}
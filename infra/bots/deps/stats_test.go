@@ -0,0 +1,111 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+func TestSummary_CommittedEntries(t *testing.T) {
+	summary := Summary()
+	assert.Equal(t, 3, summary[VersionCIPD])
+	assert.Equal(t, len(deps)-3, summary[VersionGit])
+	assert.Zero(t, summary[VersionUnknown])
+}
+
+func TestSchemePercentages_SumTo100(t *testing.T) {
+	total := 0.0
+	for _, pct := range SchemePercentages() {
+		total += pct
+	}
+	assert.InDelta(t, 100, total, 0.01*float64(len(Summary())))
+	assert.InDelta(t, 300.0/float64(len(deps)), SchemePercentages()[VersionCIPD], 0.005)
+}
+
+func TestOneLineSummary(t *testing.T) {
+	git := 0
+	hosts := map[string]bool{}
+	for _, e := range deps {
+		if ClassifyVersion(e.Version) == VersionGit {
+			git++
+			hosts[Host(*e)] = true
+		}
+	}
+	expect := fmt.Sprintf("deps: %d entries (%d git, 3 cipd) across %d hosts", len(deps), git, len(hosts))
+	assert.Equal(t, expect, OneLineSummary())
+	assert.Regexp(t, `^deps: \d+ entries \(\d+ git, \d+ cipd\) across \d+ hosts$`, OneLineSummary())
+}
+
+func TestTotalPathSegments(t *testing.T) {
+	assert.Equal(t, 6, totalPathSegments(deps_parser.DepsEntries{
+		"a": {Id: "a", Path: "bin"},
+		"b": {Id: "b", Path: "third_party/externals/b"},
+		"c": {Id: "c", Path: "infra/skia-infra/"},
+	}))
+
+	expect := 0
+	for _, e := range deps {
+		expect += len(strings.Split(e.Path, "/"))
+	}
+	assert.Positive(t, TotalPathSegments())
+	assert.Equal(t, expect, TotalPathSegments())
+}
+
+func TestDistinctCIPDEpochs(t *testing.T) {
+	assert.Equal(t, []int{2}, DistinctCIPDEpochs())
+
+	assert.Equal(t, []int{1, 2, 3}, distinctCIPDEpochs(deps_parser.DepsEntries{
+		"a": {Id: "a", Version: "version:3@1.0"},
+		"b": {Id: "b", Version: "version:1@1.0"},
+		"c": {Id: "c", Version: "version:2@1.0"},
+		"d": {Id: "d", Version: "version:2@2.0"},
+		"e": {Id: "e", Version: "git_revision:ca6066d7097cf6a175b48c03d5e9c24c1ee0262f"},
+		"f": {Id: "example.googlesource.com/f", Version: testHash},
+	}))
+	assert.Empty(t, distinctCIPDEpochs(deps_parser.DepsEntries{}))
+}
+
+func TestVersionLengthExtremes(t *testing.T) {
+	entries := deps_parser.DepsEntries{}
+	for _, id := range []string{
+		"chromium.googlesource.com/chromium/deps/icu",
+		"dawn.googlesource.com/dawn",
+		"skia/tools/sk",
+	} {
+		entries[id] = deps[id]
+	}
+	longest, shortest := versionLengthExtremes(entries)
+	assert.Equal(t, "skia/tools/sk", longest.Id)
+	assert.Equal(t, "chromium.googlesource.com/chromium/deps/icu", shortest.Id, "ties go to the first ID")
+	assert.Len(t, shortest.Version, 40)
+
+	// The "version:" tag of ninja is shorter than a git hash.
+	longest, shortest = VersionLengthExtremes()
+	assert.Equal(t, "skia/tools/bazel_build", longest.Id)
+	assert.Equal(t, "infra/3pp/tools/ninja", shortest.Id)
+}
+
+func TestImmutablePinRatio(t *testing.T) {
+	entries := deps_parser.DepsEntries{
+		"example.googlesource.com/a": {Id: "example.googlesource.com/a", Version: testHash, Type: deps_parser.DepType_Git},
+		"example.googlesource.com/b": {Id: "example.googlesource.com/b", Version: testHash, Type: deps_parser.DepType_Git},
+		"example/cipd/pkg":           {Id: "example/cipd/pkg", Version: "Fs1jJ8-Y6DrHaCTWUDiRj8YnG-t3nIoNvkI4gqCmdKIC", Type: deps_parser.DepType_Cipd},
+		"example.googlesource.com/c": {Id: "example.googlesource.com/c", Version: "refs/heads/main", Type: deps_parser.DepType_Git},
+	}
+	assert.Equal(t, 0.75, immutablePinRatio(entries))
+
+	delete(entries, "example.googlesource.com/c")
+	assert.Equal(t, 1.0, immutablePinRatio(entries))
+	assert.Equal(t, 0.0, immutablePinRatio(deps_parser.DepsEntries{}))
+
+	// The committed CIPD packages are pinned to tags.
+	assert.Equal(t, float64(len(deps)-3)/float64(len(deps)), ImmutablePinRatio())
+}
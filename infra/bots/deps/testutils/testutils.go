@@ -0,0 +1,43 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package testutils provides test helpers for code which uses the deps package.
+package testutils
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+	"go.skia.org/skia/infra/bots/deps"
+)
+
+// AssertEqualJSON fails the test if the given entries differ from those in the
+// JSON file at expectedPath, as written by deps.SaveSnapshot. The failure message
+// lists each added, removed and changed entry, relative to the expectation.
+func AssertEqualJSON(t testing.TB, expectedPath string, entries deps_parser.DepsEntries) {
+	t.Helper()
+	expected, err := deps.LoadSnapshot(expectedPath)
+	if err != nil {
+		t.Fatalf("loading expected entries: %s", err)
+		return
+	}
+	d := deps.Diff(expected, entries)
+	if len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0 {
+		return
+	}
+	var lines []string
+	for _, e := range d.Added {
+		lines = append(lines, fmt.Sprintf("+ %s: version %q, path %q", e.Id, e.Version, e.Path))
+	}
+	for _, e := range d.Removed {
+		lines = append(lines, fmt.Sprintf("- %s: version %q, path %q", e.Id, e.Version, e.Path))
+	}
+	for _, c := range d.Changed {
+		lines = append(lines, fmt.Sprintf("~ %s: version %q -> %q, path %q -> %q", c.New.Id, c.Old.Version, c.New.Version, c.Old.Path, c.New.Path))
+	}
+	t.Errorf("entries do not match %s:\n%s", expectedPath, strings.Join(lines, "\n"))
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package testutils
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+	"go.skia.org/skia/infra/bots/deps"
+)
+
+// recordingTB is a testing.TB which records failures instead of failing the
+// test.
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Fatalf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertEqualJSON_Matching_Passes(t *testing.T) {
+	expectedPath := filepath.Join(t.TempDir(), "expected.json")
+	require.NoError(t, deps.SaveSnapshot(expectedPath, deps.Entries()))
+
+	tb := &recordingTB{TB: t}
+	AssertEqualJSON(tb, expectedPath, deps.Entries())
+	assert.Empty(t, tb.failures)
+}
+
+func TestAssertEqualJSON_Mismatching_FailsWithDiff(t *testing.T) {
+	expectedPath := filepath.Join(t.TempDir(), "expected.json")
+	require.NoError(t, deps.SaveSnapshot(expectedPath, deps_parser.DepsEntries{
+		"a": {Id: "a", Version: "version:2@1.0", Path: "bin"},
+		"b": {Id: "b", Version: "version:2@1.0", Path: "bin"},
+	}))
+
+	tb := &recordingTB{TB: t}
+	AssertEqualJSON(tb, expectedPath, deps_parser.DepsEntries{
+		"a": {Id: "a", Version: "version:2@1.1", Path: "bin"},
+		"c": {Id: "c", Version: "version:2@1.0", Path: "bin"},
+	})
+	require.Len(t, tb.failures, 1)
+	assert.Equal(t, "entries do not match "+expectedPath+`:
++ c: version "version:2@1.0", path "bin"
+- b: version "version:2@1.0", path "bin"
+~ a: version "version:2@1.0" -> "version:2@1.1", path "bin" -> "bin"`, tb.failures[0])
+}
+
+func TestAssertEqualJSON_MissingFile_Fails(t *testing.T) {
+	tb := &recordingTB{TB: t}
+	AssertEqualJSON(tb, filepath.Join(t.TempDir(), "missing.json"), deps.Entries())
+	require.Len(t, tb.failures, 1)
+	assert.Contains(t, tb.failures[0], "loading expected entries")
+}
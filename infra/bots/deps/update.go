@@ -0,0 +1,156 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"sort"
+	"strings"
+
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+	"go.skia.org/infra/go/skerr"
+)
+
+// SetVersions returns a copy of the given entries with the versions of the
+// dependencies in updates, which maps IDs to new versions, replaced. Every
+// update is checked before any is applied: if any ID is unknown or any new
+// version uses a different scheme than the current one, an error is returned.
+// The given entries are never modified.
+func SetVersions(entries deps_parser.DepsEntries, updates map[string]string) (deps_parser.DepsEntries, error) {
+	ids := make([]string, 0, len(updates))
+	for id := range updates {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	resolved := make(map[string]string, len(updates))
+	for _, id := range ids {
+		e := entries.Get(id)
+		if e == nil {
+			return nil, skerr.Fmt("unknown dependency %q", id)
+		}
+		version := updates[id]
+		if oldKind, newKind := ClassifyVersion(e.Version), ClassifyVersion(version); oldKind != newKind {
+			return nil, skerr.Fmt("cannot set %s to %q: version scheme %s does not match current scheme %s", e.Id, version, newKind, oldKind)
+		}
+		resolved[e.Id] = version
+	}
+
+	rv := make(deps_parser.DepsEntries, len(entries))
+	for id, e := range entries {
+		cp := *e
+		if version, ok := resolved[cp.Id]; ok {
+			cp.Version = version
+		}
+		rv[id] = &cp
+	}
+	return rv, nil
+}
+
+// ApplyLKG returns a copy of the given entries with the last-known-good
+// versions recorded by CI, which map IDs to versions, applied on top. As with
+// SetVersions, nothing is applied if any version is invalid.
+func ApplyLKG(entries deps_parser.DepsEntries, lkg map[string]string) (deps_parser.DepsEntries, error) {
+	rv, err := SetVersions(entries, lkg)
+	if err != nil {
+		return nil, skerr.Wrapf(err, "applying last-known-good versions")
+	}
+	return rv, nil
+}
+
+// ApplyIfNewer is like SetVersions, but only applies the updates whose version
+// is newer than the current one, returning the updated copy of the entries
+// along with the requested IDs, sorted, which were skipped. CIPD versions are
+// ordered using CompareCIPDVersions, and a git version is newer if the current
+// version is its ancestor according to the given AncestryChecker.
+func ApplyIfNewer(ctx context.Context, git AncestryChecker, entries deps_parser.DepsEntries, updates map[string]string) (deps_parser.DepsEntries, []string, error) {
+	// Check every update before comparing any versions.
+	if _, err := SetVersions(entries, updates); err != nil {
+		return nil, nil, skerr.Wrap(err)
+	}
+	ids := make([]string, 0, len(updates))
+	for id := range updates {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	newer := make(map[string]string, len(updates))
+	var skipped []string
+	for _, id := range ids {
+		e := entries.Get(id)
+		version := updates[id]
+		isNewer := false
+		if version != e.Version {
+			if ClassifyVersion(version) == VersionCIPD {
+				cmp, err := CompareCIPDVersions(version, e.Version)
+				if err != nil {
+					return nil, nil, skerr.Wrapf(err, "comparing versions of %s", e.Id)
+				}
+				isNewer = cmp > 0
+			} else {
+				var err error
+				isNewer, err = git.IsAncestor(ctx, RepoURL(*e), e.Version, version)
+				if err != nil {
+					return nil, nil, skerr.Wrapf(err, "comparing versions of %s", e.Id)
+				}
+			}
+		}
+		if isNewer {
+			newer[id] = version
+		} else {
+			skipped = append(skipped, id)
+		}
+	}
+	rv, err := SetVersions(entries, newer)
+	if err != nil {
+		return nil, nil, skerr.Wrap(err)
+	}
+	return rv, skipped, nil
+}
+
+// ParseOverrideFile reads version overrides from a text file containing lines
+// of the form "id=version". Blank lines and lines starting with "#" are
+// ignored.
+func ParseOverrideFile(r io.Reader) (map[string]string, error) {
+	rv := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		id, version, ok := strings.Cut(line, "=")
+		id = strings.TrimSpace(id)
+		version = strings.TrimSpace(version)
+		if !ok || id == "" || version == "" {
+			return nil, skerr.Fmt("line %d: expected \"id=version\" but got %q", lineNum, line)
+		}
+		if _, ok := rv[id]; ok {
+			return nil, skerr.Fmt("line %d: duplicate override for %q", lineNum, id)
+		}
+		rv[id] = version
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, skerr.Wrap(err)
+	}
+	return rv, nil
+}
+
+// ApplyOverrides returns a copy of the package-local entries with the given
+// overrides, which map IDs to versions, applied as in SetVersions. The
+// package-local entries themselves are never modified, so that they are safe
+// for concurrent use.
+func ApplyOverrides(overrides map[string]string) (deps_parser.DepsEntries, error) {
+	rv, err := SetVersions(deps, overrides)
+	if err != nil {
+		return nil, skerr.Wrap(err)
+	}
+	return rv, nil
+}
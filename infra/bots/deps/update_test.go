@@ -0,0 +1,158 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	vulkanHeaders = "chromium.googlesource.com/external/github.com/KhronosGroup/Vulkan-Headers"
+	vulkanTools   = "chromium.googlesource.com/external/github.com/KhronosGroup/Vulkan-Tools"
+	newHash       = "0123456789abcdef0123456789abcdef01234567"
+)
+
+func TestSetVersions_ValidBatch_AllApplied(t *testing.T) {
+	oldTools := deps[vulkanTools].Version
+	updated, err := SetVersions(deps, map[string]string{
+		vulkanHeaders:                     newHash,
+		"https://" + vulkanTools + ".git": "fedcba9876543210fedcba9876543210fedcba98",
+		"infra/3pp/tools/ninja":           "version:2@1.12.2",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, newHash, updated[vulkanHeaders].Version)
+	assert.Equal(t, "fedcba9876543210fedcba9876543210fedcba98", updated[vulkanTools].Version)
+	assert.Equal(t, "version:2@1.12.2", updated["infra/3pp/tools/ninja"].Version)
+	assert.Equal(t, oldTools, deps[vulkanTools].Version, "input must not be modified")
+}
+
+func TestSetVersions_UnknownId_NothingApplied(t *testing.T) {
+	updated, err := SetVersions(deps, map[string]string{
+		vulkanHeaders:                newHash,
+		"example.com/does-not-exist": newHash,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown dependency "example.com/does-not-exist"`)
+	assert.Nil(t, updated)
+	assert.NotEqual(t, newHash, deps[vulkanHeaders].Version)
+}
+
+func TestSetVersions_SchemeMismatch_Error(t *testing.T) {
+	_, err := SetVersions(deps, map[string]string{
+		vulkanHeaders: "version:2@1.0",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "version scheme cipd does not match current scheme git")
+}
+
+func TestApplyLKG_OverlayTakesEffect(t *testing.T) {
+	lkg, err := ApplyLKG(deps, map[string]string{
+		vulkanHeaders:   newHash,
+		"skia/tools/sk": "git_revision:" + newHash,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, newHash, lkg[vulkanHeaders].Version)
+	assert.Equal(t, "git_revision:"+newHash, lkg["skia/tools/sk"].Version)
+	assert.Equal(t, deps[vulkanTools].Version, lkg[vulkanTools].Version)
+
+	_, err = ApplyLKG(deps, map[string]string{"skia/tools/sk": newHash})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "applying last-known-good versions")
+}
+
+func TestParseOverrideFile_WellFormed(t *testing.T) {
+	overrides, err := ParseOverrideFile(strings.NewReader(`
+# Roll the Vulkan headers.
+` + vulkanHeaders + `=` + newHash + `
+
+  infra/3pp/tools/ninja = version:2@1.12.2
+`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		vulkanHeaders:           newHash,
+		"infra/3pp/tools/ninja": "version:2@1.12.2",
+	}, overrides)
+}
+
+func TestParseOverrideFile_Malformed_Error(t *testing.T) {
+	_, err := ParseOverrideFile(strings.NewReader("# comment\n" + vulkanHeaders + "\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2: expected")
+}
+
+func TestApplyOverrides(t *testing.T) {
+	_, err := ApplyOverrides(map[string]string{vulkanHeaders: "version:2@1.0"})
+	require.Error(t, err)
+
+	updated, err := ApplyOverrides(map[string]string{vulkanHeaders: newHash})
+	require.NoError(t, err)
+	assert.Equal(t, newHash, updated[vulkanHeaders].Version)
+
+	// The package-local entries are unchanged.
+	e, err := Get(vulkanHeaders)
+	require.NoError(t, err)
+	assert.NotEqual(t, newHash, e.Version)
+}
+
+func TestApplyIfNewer_CIPD(t *testing.T) {
+	const ninja = "infra/3pp/tools/ninja"
+	test := func(name, version string, expectApplied bool) {
+		t.Run(name, func(t *testing.T) {
+			updated, skipped, err := ApplyIfNewer(context.Background(), fakeGit{}, deps, map[string]string{ninja: version})
+			require.NoError(t, err)
+			if expectApplied {
+				assert.Empty(t, skipped)
+				assert.Equal(t, version, updated[ninja].Version)
+			} else {
+				assert.Equal(t, []string{ninja}, skipped)
+				assert.Equal(t, deps[ninja].Version, updated[ninja].Version)
+			}
+		})
+	}
+	test("newer", "version:2@1.12.10", true)
+	test("older", "version:2@1.9.0", false)
+	test("equal", deps[ninja].Version, false)
+}
+
+func TestApplyIfNewer_Git(t *testing.T) {
+	const older = "fedcba9876543210fedcba9876543210fedcba98"
+	repo := "https://" + vulkanHeaders
+	git := fakeGit{ancestors: map[string]bool{
+		repo + "@" + deps[vulkanHeaders].Version + ".." + newHash: true,
+		repo + "@" + older + ".." + deps[vulkanHeaders].Version:   true,
+	}}
+	test := func(name, version string, expectApplied bool) {
+		t.Run(name, func(t *testing.T) {
+			updated, skipped, err := ApplyIfNewer(context.Background(), git, deps, map[string]string{vulkanHeaders: version})
+			require.NoError(t, err)
+			if expectApplied {
+				assert.Empty(t, skipped)
+				assert.Equal(t, version, updated[vulkanHeaders].Version)
+			} else {
+				assert.Equal(t, []string{vulkanHeaders}, skipped)
+				assert.Equal(t, deps[vulkanHeaders].Version, updated[vulkanHeaders].Version)
+			}
+		})
+	}
+	test("newer", newHash, true)
+	test("older", older, false)
+	test("equal", deps[vulkanHeaders].Version, false)
+}
+
+func TestApplyIfNewer_Invalid_Error(t *testing.T) {
+	_, _, err := ApplyIfNewer(context.Background(), fakeGit{}, deps, map[string]string{"unknown/dep": newHash})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown dependency "unknown/dep"`)
+
+	_, _, err = ApplyIfNewer(context.Background(), fakeGit{}, deps, map[string]string{"skia/tools/sk": "git_revision:" + newHash})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "comparing versions of skia/tools/sk")
+}
@@ -0,0 +1,242 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+	"go.skia.org/infra/go/skerr"
+)
+
+// validationRules are applied to every entry by Validate. Each rule returns a
+// non-nil error describing the problem if the entry is invalid.
+var validationRules = []func(*deps_parser.DepsEntry) error{
+	validateCIPDTag,
+	validateWindowsPath,
+	validateControlChars,
+	validateNonASCII,
+	validateHostlessGitHash,
+	validateCIPDPackage,
+	validateTagLikeVersion,
+	validateVersionScheme,
+	validateLowercaseHash,
+	validatePlaceholderHash,
+	validateReservedTopLevel,
+}
+
+// Validate checks the given entries for common mistakes in DEPS, eg. malformed
+// versions. Returns an error describing every problem found, or nil if all
+// entries are valid.
+func Validate(entries deps_parser.DepsEntries) error {
+	ids := make([]string, 0, len(entries))
+	for id := range entries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var problems []string
+	for _, id := range ids {
+		for _, rule := range validationRules {
+			if err := rule(entries[id]); err != nil {
+				problems = append(problems, err.Error())
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return skerr.Fmt("found %d invalid DEPS entries:\n%s", len(problems), strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+// ValidateIds checks that the IDs of the given entries are acceptable to
+// gclient, returning an error, sorted by ID, for each ID which is empty or
+// contains whitespace, control characters or empty path segments, eg.
+// "chromium.googlesource.com//icu".
+func ValidateIds(entries deps_parser.DepsEntries) []error {
+	var errs []error
+	for _, e := range sortedEntries(entries) {
+		switch {
+		case e.Id == "":
+			errs = append(errs, skerr.Fmt("ID is empty (path %q)", e.Path))
+		case strings.IndexFunc(e.Id, unicode.IsSpace) >= 0:
+			errs = append(errs, skerr.Fmt("ID %q contains whitespace", e.Id))
+		case strings.IndexFunc(e.Id, unicode.IsControl) >= 0:
+			errs = append(errs, skerr.Fmt("ID %q contains control characters", e.Id))
+		case slices.Contains(strings.Split(e.Id, "/"), ""):
+			errs = append(errs, skerr.Fmt("ID %q contains an empty path segment", e.Id))
+		}
+	}
+	return errs
+}
+
+// validateCIPDTag flags CIPD versions of the form "version:<epoch>@<tag>"
+// whose tag is empty, eg. "version:2@".
+func validateCIPDTag(e *deps_parser.DepsEntry) error {
+	_, value, ok := strings.Cut(e.Version, ":")
+	if !ok {
+		return nil
+	}
+	if _, tag, ok := strings.Cut(value, "@"); ok && tag == "" {
+		return skerr.Fmt("%s: CIPD version %q has an empty tag", e.Id, e.Version)
+	}
+	return nil
+}
+
+// windowsReservedNames are device names which Windows does not allow as file or
+// directory names, regardless of case or extension.
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// validateWindowsPath flags Paths containing a segment which cannot be checked
+// out on Windows, eg. "third_party/nul".
+func validateWindowsPath(e *deps_parser.DepsEntry) error {
+	for _, segment := range strings.Split(e.Path, "/") {
+		name, _, _ := strings.Cut(segment, ".")
+		if windowsReservedNames[strings.ToUpper(name)] {
+			return skerr.Fmt("%s: path %q contains Windows-reserved name %q", e.Id, e.Path, segment)
+		}
+	}
+	return nil
+}
+
+// validateControlChars flags fields containing control characters, eg. a
+// carriage return which was introduced by editing DEPS on Windows.
+func validateControlChars(e *deps_parser.DepsEntry) error {
+	for _, field := range []struct{ name, value string }{
+		{"ID", e.Id},
+		{"version", e.Version},
+		{"path", e.Path},
+	} {
+		if strings.IndexFunc(field.value, unicode.IsControl) >= 0 {
+			return skerr.Fmt("%s: %s %q contains control characters", e.Id, field.name, field.value)
+		}
+	}
+	return nil
+}
+
+// validateNonASCII flags fields containing non-ASCII characters, which may be
+// used to disguise a malicious ID as a trusted one, eg. with a Cyrillic "а" in
+// place of a Latin "a".
+func validateNonASCII(e *deps_parser.DepsEntry) error {
+	for _, field := range []struct{ name, value string }{
+		{"ID", e.Id},
+		{"version", e.Version},
+		{"path", e.Path},
+	} {
+		if i := strings.IndexFunc(field.value, func(r rune) bool { return r > unicode.MaxASCII }); i >= 0 {
+			return skerr.Fmt("%s: %s %q contains non-ASCII character %U", e.Id, field.name, field.value, []rune(field.value[i:])[0])
+		}
+	}
+	return nil
+}
+
+// validateHostlessGitHash flags entries whose ID has no host, and therefore
+// looks like a CIPD package, but which are pinned to a bare git hash.
+func validateHostlessGitHash(e *deps_parser.DepsEntry) error {
+	if Host(*e) == "" && ClassifyVersion(e.Version) == VersionGit {
+		return skerr.Fmt("%s: ID has no host, as for a CIPD package, but version %q is a git hash", e.Id, e.Version)
+	}
+	return nil
+}
+
+// validateCIPDPackage flags CIPD packages whose package path is empty or
+// malformed, eg. "infra/tools@latest".
+func validateCIPDPackage(e *deps_parser.DepsEntry) error {
+	pkg, ok := CIPDPackage(*e)
+	if !ok {
+		return nil
+	}
+	if pkg == "" {
+		return skerr.Fmt("%s: CIPD package at path %q has an empty package name", e.Id, e.Path)
+	}
+	if strings.Contains(pkg, "@") || slices.Contains(strings.Split(pkg, "/"), "") {
+		return skerr.Fmt("%s: malformed CIPD package name", e.Id)
+	}
+	return nil
+}
+
+var hexRegex = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+
+// tagLikeRegex matches versions which look like release tags, eg. "v2.1.0".
+var tagLikeRegex = regexp.MustCompile(`^v?\d+\.\d+`)
+
+// validateTagLikeVersion flags versions which look like git tags. gclient may
+// accept them, but tags can be moved, so they are not reproducible.
+func validateTagLikeVersion(e *deps_parser.DepsEntry) error {
+	if tagLikeRegex.MatchString(e.Version) {
+		return skerr.Fmt("%s: version %q looks like a git tag, which is not reproducible; pin a commit hash instead", e.Id, e.Version)
+	}
+	return nil
+}
+
+// validateVersionScheme flags versions which are neither git hashes nor CIPD
+// tags. Hex strings of the wrong length are reported separately, since they
+// usually indicate a hash which was truncated or mangled when pasted. Tag-like
+// versions are left to validateTagLikeVersion.
+func validateVersionScheme(e *deps_parser.DepsEntry) error {
+	if ClassifyVersion(e.Version) != VersionUnknown || tagLikeRegex.MatchString(e.Version) {
+		return nil
+	}
+	if hexRegex.MatchString(e.Version) {
+		return skerr.Fmt("%s: version %q is a hex string of length %d, but git hashes have 40 characters", e.Id, e.Version, len(e.Version))
+	}
+	return skerr.Fmt("%s: version %q is neither a git hash nor a CIPD tag", e.Id, e.Version)
+}
+
+// validateLowercaseHash flags git hashes containing uppercase hex digits, which
+// git accepts but which are not canonical. See CanonicalizeVersion.
+func validateLowercaseHash(e *deps_parser.DepsEntry) error {
+	if canonical := CanonicalizeVersion(e.Version); canonical != e.Version {
+		return skerr.Fmt("%s: git hash %q contains uppercase characters; use %q", e.Id, e.Version, canonical)
+	}
+	return nil
+}
+
+// validatePlaceholderHash flags git hashes consisting of a single repeated
+// character, eg. all zeroes, which are placeholders rather than real commits.
+func validatePlaceholderHash(e *deps_parser.DepsEntry) error {
+	if ClassifyVersion(e.Version) != VersionGit {
+		return nil
+	}
+	if strings.Count(strings.ToLower(e.Version), strings.ToLower(e.Version[:1])) == len(e.Version) {
+		return skerr.Fmt("%s: git hash %q is a placeholder", e.Id, e.Version)
+	}
+	return nil
+}
+
+// ReservedTopLevel are top-level directories of the Skia checkout which
+// dependencies may not be synced into, since they hold Skia's own sources.
+// Directories like "bin" and "buildtools" are deliberately shared with
+// dependencies and are not listed.
+var ReservedTopLevel = map[string]bool{
+	"gm":      true,
+	"gn":      true,
+	"include": true,
+	"modules": true,
+	"src":     true,
+	"tests":   true,
+	"tools":   true,
+}
+
+// validateReservedTopLevel flags Paths whose top-level directory is listed in
+// ReservedTopLevel.
+func validateReservedTopLevel(e *deps_parser.DepsEntry) error {
+	top, _, _ := strings.Cut(e.Path, "/")
+	if ReservedTopLevel[top] {
+		return skerr.Fmt("%s: path %q is inside reserved top-level directory %q", e.Id, e.Path, top)
+	}
+	return nil
+}
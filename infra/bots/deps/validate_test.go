@@ -0,0 +1,169 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+// validateOne runs Validate over a set containing only the given entry.
+func validateOne(id, version, path string) error {
+	return Validate(deps_parser.DepsEntries{
+		id: {Id: id, Version: version, Path: path},
+	})
+}
+
+func TestValidate_CommittedEntries_Valid(t *testing.T) {
+	require.NoError(t, Validate(deps))
+}
+
+func TestValidate_CIPDTag(t *testing.T) {
+	require.NoError(t, validateOne("infra/3pp/tools/ninja", "version:2@1.0", "bin"))
+
+	err := validateOne("infra/3pp/tools/ninja", "version:2@", "bin")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `CIPD version "version:2@" has an empty tag`)
+}
+
+func TestValidate_WindowsReservedPath(t *testing.T) {
+	require.NoError(t, validateOne("example.googlesource.com/dep", "364118a1d9da24bb5b770ac3d762ac144d6da5a4", "third_party/externals/null"))
+
+	err := validateOne("example.googlesource.com/dep", "364118a1d9da24bb5b770ac3d762ac144d6da5a4", "third_party/nul/dep")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `contains Windows-reserved name "nul"`)
+}
+
+func TestValidate_ControlChars(t *testing.T) {
+	err := validateOne("example.googlesource.com/dep", "364118a1d9da24bb5b770ac3d762ac144d6da5a4\r", "third_party/externals/dep")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "version \"364118a1d9da24bb5b770ac3d762ac144d6da5a4\\r\" contains control characters")
+}
+
+func TestValidate_NonASCII(t *testing.T) {
+	require.NoError(t, validateOne("chromium.googlesource.com/chromium/deps/icu", testHash, "third_party/externals/icu"))
+
+	// The "е" in "googlesource" is CYRILLIC SMALL LETTER IE.
+	err := validateOne("chromium.googlesourcе.com/chromium/deps/icu", testHash, "third_party/externals/icu")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ID \"chromium.googlesourcе.com/chromium/deps/icu\" contains non-ASCII character U+0435")
+}
+
+func TestValidate_HostlessGitHash(t *testing.T) {
+	require.NoError(t, validateOne("infra/3pp/tools/ninja", "version:2@1.12.1.chromium.4", "bin"))
+
+	err := validateOne("infra/3pp/tools/ninja", "364118a1d9da24bb5b770ac3d762ac144d6da5a4", "bin")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ID has no host, as for a CIPD package, but version \"364118a1d9da24bb5b770ac3d762ac144d6da5a4\" is a git hash")
+}
+
+func TestValidate_CIPDPackage(t *testing.T) {
+	validateCIPD := func(id, version, path string) error {
+		return Validate(deps_parser.DepsEntries{
+			id: {Id: id, Version: version, Path: path, Type: deps_parser.DepType_Cipd},
+		})
+	}
+	require.NoError(t, validateCIPD("infra/3pp/tools/ninja", "version:2@1.12.1.chromium.4", "bin"))
+
+	err := validateCIPD("infra/3pp/tools/ninja@latest", "version:2@1.12.1.chromium.4", "bin")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "infra/3pp/tools/ninja@latest: malformed CIPD package name")
+
+	err = validateCIPD("infra//ninja", "version:2@1.12.1.chromium.4", "bin")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "infra//ninja: malformed CIPD package name")
+
+	err = validateCIPD("", "version:2@1.12.1.chromium.4", "bin")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `: CIPD package at path "bin" has an empty package name`)
+}
+
+func TestValidate_LowercaseHash(t *testing.T) {
+	require.NoError(t, validateOne("example.googlesource.com/dep", testHash, "third_party/externals/dep"))
+
+	err := validateOne("example.googlesource.com/dep", "364118A1D9DA24BB5B770AC3D762AC144D6DA5A4", "third_party/externals/dep")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `git hash "364118A1D9DA24BB5B770AC3D762AC144D6DA5A4" contains uppercase characters; use "364118a1d9da24bb5b770ac3d762ac144d6da5a4"`)
+}
+
+func TestValidate_PlaceholderHash(t *testing.T) {
+	require.NoError(t, validateOne("example.googlesource.com/dep", testHash, "third_party/externals/dep"))
+
+	for _, version := range []string{
+		"0000000000000000000000000000000000000000",
+		"ffffffffffffffffffffffffffffffffffffffff",
+	} {
+		err := validateOne("example.googlesource.com/dep", version, "third_party/externals/dep")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "git hash \""+version+"\" is a placeholder")
+	}
+}
+
+func TestValidate_TagLikeVersion(t *testing.T) {
+	require.NoError(t, validateOne("example.googlesource.com/dep", testHash, "third_party/externals/dep"))
+
+	for _, version := range []string{"v2.1.0", "1.2"} {
+		err := validateOne("example.googlesource.com/dep", version, "third_party/externals/dep")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "version \""+version+"\" looks like a git tag")
+		assert.Contains(t, err.Error(), "found 1 invalid DEPS entries", "not also reported as an unknown scheme")
+	}
+}
+
+func TestValidate_VersionScheme(t *testing.T) {
+	test := func(name, version, expectErr string) {
+		t.Run(name, func(t *testing.T) {
+			err := validateOne("example.googlesource.com/dep", version, "third_party/externals/dep")
+			if expectErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), expectErr)
+			}
+		})
+	}
+	test("40 chars", "364118a1d9da24bb5b770ac3d762ac144d6da5a4", "")
+	test("38 chars", "364118a1d9da24bb5b770ac3d762ac144d6da5", "hex string of length 38, but git hashes have 40 characters")
+	test("42 chars", "364118a1d9da24bb5b770ac3d762ac144d6da5a4a4", "hex string of length 42, but git hashes have 40 characters")
+	test("not hex", "some-branch", "neither a git hash nor a CIPD tag")
+}
+
+func TestValidateIds(t *testing.T) {
+	assert.Empty(t, ValidateIds(deps))
+
+	errs := ValidateIds(deps_parser.DepsEntries{
+		"example.googlesource.com/ok":   {Id: "example.googlesource.com/ok", Path: "a"},
+		"example.googlesource.com/a b":  {Id: "example.googlesource.com/a b", Path: "b"},
+		"example.googlesource.com//dep": {Id: "example.googlesource.com//dep", Path: "c"},
+		"example.googlesource.com/dep/": {Id: "example.googlesource.com/dep/", Path: "d"},
+		"example.googlesource.com/\x7f": {Id: "example.googlesource.com/\x7f", Path: "e"},
+		"":                              {Id: "", Path: "f"},
+	})
+	require.Len(t, errs, 5)
+	assert.Contains(t, errs[0].Error(), `ID is empty (path "f")`)
+	assert.Contains(t, errs[1].Error(), `ID "example.googlesource.com//dep" contains an empty path segment`)
+	assert.Contains(t, errs[2].Error(), `ID "example.googlesource.com/a b" contains whitespace`)
+	assert.Contains(t, errs[3].Error(), `ID "example.googlesource.com/dep/" contains an empty path segment`)
+	assert.Contains(t, errs[4].Error(), `ID "example.googlesource.com/\x7f" contains control characters`)
+}
+
+func TestValidate_ReservedTopLevel(t *testing.T) {
+	require.NoError(t, validateOne("infra/3pp/tools/ninja", "version:2@1.12.1.chromium.4", "bin"))
+
+	err := validateOne("example.googlesource.com/dep", testHash, "src/dep")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `path "src/dep" is inside reserved top-level directory "src"`)
+
+	old := ReservedTopLevel
+	defer func() { ReservedTopLevel = old }()
+	ReservedTopLevel = map[string]bool{"bin": true}
+	err = validateOne("infra/3pp/tools/ninja", "version:2@1.12.1.chromium.4", "bin")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `path "bin" is inside reserved top-level directory "bin"`)
+}
@@ -0,0 +1,199 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+	"go.skia.org/infra/go/skerr"
+)
+
+// VersionKind describes the scheme used by a pinned version.
+type VersionKind int
+
+const (
+	// VersionUnknown indicates that the version matches no known scheme.
+	VersionUnknown VersionKind = iota
+	// VersionGit indicates a full git commit hash.
+	VersionGit
+	// VersionCIPD indicates a CIPD tag, eg. "version:2@1.12.1" or
+	// "git_revision:<hash>".
+	VersionCIPD
+)
+
+// String implements fmt.Stringer.
+func (k VersionKind) String() string {
+	switch k {
+	case VersionGit:
+		return "git"
+	case VersionCIPD:
+		return "cipd"
+	default:
+		return "unknown"
+	}
+}
+
+// gitRevisionPrefix is the CIPD tag used by packages built from a git
+// revision, eg. "git_revision:<hash>".
+const gitRevisionPrefix = "git_revision:"
+
+var gitHashRegex = regexp.MustCompile(`^[0-9a-fA-F]{40}$`)
+
+// ClassifyVersion returns the VersionKind of the given version string.
+func ClassifyVersion(version string) VersionKind {
+	if gitHashRegex.MatchString(version) {
+		return VersionGit
+	}
+	if key, _, ok := strings.Cut(version, ":"); ok && key != "" {
+		return VersionCIPD
+	}
+	return VersionUnknown
+}
+
+// CanonicalizeVersion returns the canonical form of the given version, ie.
+// lowercased if it is a git hash. Other versions are returned unchanged.
+func CanonicalizeVersion(v string) string {
+	if ClassifyVersion(v) == VersionGit {
+		return strings.ToLower(v)
+	}
+	return v
+}
+
+// IsGitRevisionCIPD returns true if the given entry is a CIPD package, ie. its
+// ID has no host, which is pinned to the git revision it was built from, eg.
+// "git_revision:<hash>".
+func IsGitRevisionCIPD(e deps_parser.DepsEntry) bool {
+	return Host(e) == "" && strings.HasPrefix(e.Version, gitRevisionPrefix)
+}
+
+// CompareCIPDVersions compares two CIPD versions of the form
+// "version:<epoch>@<tag>", returning -1, 0 or 1 if a is older than, the same
+// as, or newer than b. Epochs are compared numerically and tags in natural
+// order, so that "version:2@1.10" is newer than "version:2@1.9". Other CIPD
+// tags, eg. "git_revision:<hash>", cannot be ordered and result in an error.
+func CompareCIPDVersions(a, b string) (int, error) {
+	epochA, tagA, err := parseCIPDVersionTag(a)
+	if err != nil {
+		return 0, skerr.Wrap(err)
+	}
+	epochB, tagB, err := parseCIPDVersionTag(b)
+	if err != nil {
+		return 0, skerr.Wrap(err)
+	}
+	switch {
+	case epochA != epochB:
+		if epochA < epochB {
+			return -1, nil
+		}
+		return 1, nil
+	case naturalLess(tagA, tagB):
+		return -1, nil
+	case naturalLess(tagB, tagA):
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// CIPDLag returns how far behind the newest available version each CIPD
+// package in the given entries is, keyed by ID. newest maps package IDs to
+// their newest "version:<epoch>@<tag>" versions. Tags are compared component
+// by component, split on ".", and the lag is the difference in the first
+// numeric component which differs, eg. "1.12.1" is 1 behind "1.12.2" and 2
+// behind "1.14.0". Packages which are up to date or ahead have a lag of 0, and
+// a newer epoch or non-numeric component counts as 1. Packages which are not
+// in newest or whose versions cannot be ordered are omitted.
+func CIPDLag(entries deps_parser.DepsEntries, newest map[string]string) map[string]int {
+	rv := map[string]int{}
+	for _, e := range entries {
+		latest, ok := newest[e.Id]
+		if !ok {
+			continue
+		}
+		cmp, err := CompareCIPDVersions(e.Version, latest)
+		if err != nil {
+			continue
+		}
+		rv[e.Id] = 0
+		if cmp >= 0 {
+			continue
+		}
+		epoch, tag, _ := parseCIPDVersionTag(e.Version)
+		latestEpoch, latestTag, _ := parseCIPDVersionTag(latest)
+		rv[e.Id] = 1
+		if epoch != latestEpoch {
+			continue
+		}
+		parts, latestParts := strings.Split(tag, "."), strings.Split(latestTag, ".")
+		for i := 0; i < len(parts) && i < len(latestParts); i++ {
+			if parts[i] == latestParts[i] {
+				continue
+			}
+			n, err := strconv.Atoi(parts[i])
+			latestN, latestErr := strconv.Atoi(latestParts[i])
+			if err == nil && latestErr == nil && latestN > n {
+				rv[e.Id] = latestN - n
+			}
+			break
+		}
+	}
+	return rv
+}
+
+// parseCIPDVersionTag splits a CIPD version of the form
+// "version:<epoch>@<tag>" into its epoch and tag.
+func parseCIPDVersionTag(version string) (int, string, error) {
+	value, ok := strings.CutPrefix(version, "version:")
+	if !ok {
+		return 0, "", skerr.Fmt("cannot order CIPD version %q: not a \"version:\" tag", version)
+	}
+	epochStr, tag, ok := strings.Cut(value, "@")
+	if !ok {
+		return 0, "", skerr.Fmt("cannot order CIPD version %q: missing epoch", version)
+	}
+	epoch, err := strconv.Atoi(epochStr)
+	if err != nil {
+		return 0, "", skerr.Wrapf(err, "cannot order CIPD version %q: invalid epoch", version)
+	}
+	return epoch, tag, nil
+}
+
+// AnnotatedEntry wraps a DepsEntry and caches information derived from it, for
+// use in loops which would otherwise repeatedly reparse the version. The
+// embedded DepsEntry must not be modified after the first call to Kind.
+type AnnotatedEntry struct {
+	deps_parser.DepsEntry
+
+	kindOnce sync.Once
+	kind     VersionKind
+}
+
+// Annotate returns an AnnotatedEntry wrapping a copy of the given entry.
+func Annotate(e deps_parser.DepsEntry) *AnnotatedEntry {
+	return &AnnotatedEntry{DepsEntry: e}
+}
+
+// Kind returns the VersionKind of the entry's version, computing it only once.
+func (a *AnnotatedEntry) Kind() VersionKind {
+	a.kindOnce.Do(func() {
+		a.kind = ClassifyVersion(a.Version)
+	})
+	return a.kind
+}
+
+// VersionBytes returns the entry's version as a byte slice which shares memory
+// with the Version string, avoiding a copy in tight hashing loops. The returned
+// slice must not be modified.
+func VersionBytes(e deps_parser.DepsEntry) []byte {
+	if e.Version == "" {
+		return nil
+	}
+	return unsafe.Slice(unsafe.StringData(e.Version), len(e.Version))
+}
@@ -0,0 +1,128 @@
+// Copyright 2024 Google LLC
+//
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package deps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.skia.org/infra/go/depot_tools/deps_parser"
+)
+
+func TestClassifyVersion(t *testing.T) {
+	assert.Equal(t, VersionGit, ClassifyVersion("364118a1d9da24bb5b770ac3d762ac144d6da5a4"))
+	assert.Equal(t, VersionCIPD, ClassifyVersion("version:2@1.12.1.chromium.4"))
+	assert.Equal(t, VersionCIPD, ClassifyVersion("git_revision:ca6066d7097cf6a175b48c03d5e9c24c1ee0262f"))
+	assert.Equal(t, VersionUnknown, ClassifyVersion("main"))
+	assert.Equal(t, VersionUnknown, ClassifyVersion(":foo"))
+}
+
+func TestCanonicalizeVersion(t *testing.T) {
+	assert.Equal(t, "364118a1d9da24bb5b770ac3d762ac144d6da5a4", CanonicalizeVersion("364118A1D9DA24BB5B770AC3D762AC144D6DA5A4"))
+	assert.Equal(t, "364118a1d9da24bb5b770ac3d762ac144d6da5a4", CanonicalizeVersion("364118a1d9da24bb5b770ac3d762ac144d6da5a4"))
+	assert.Equal(t, "version:2@1.12.1.chromium.4", CanonicalizeVersion("version:2@1.12.1.chromium.4"))
+	assert.Equal(t, "Main", CanonicalizeVersion("Main"))
+}
+
+func TestIsGitRevisionCIPD(t *testing.T) {
+	assert.True(t, IsGitRevisionCIPD(*deps["skia/tools/sk"]))
+	assert.True(t, IsGitRevisionCIPD(*deps["skia/tools/bazel_build"]))
+	assert.False(t, IsGitRevisionCIPD(*deps["infra/3pp/tools/ninja"]))
+	assert.False(t, IsGitRevisionCIPD(*deps["dawn.googlesource.com/dawn"]))
+	assert.False(t, IsGitRevisionCIPD(deps_parser.DepsEntry{
+		Id:      "example.googlesource.com/dep",
+		Version: "git_revision:ca6066d7097cf6a175b48c03d5e9c24c1ee0262f",
+	}))
+}
+
+func TestCompareCIPDVersions(t *testing.T) {
+	test := func(a, b string, expect int) {
+		cmp, err := CompareCIPDVersions(a, b)
+		require.NoError(t, err)
+		assert.Equal(t, expect, cmp, "%s vs %s", a, b)
+	}
+	test("version:2@1.12.1.chromium.4", "version:2@1.12.1.chromium.4", 0)
+	test("version:2@1.12.1.chromium.4", "version:2@1.12.2", -1)
+	test("version:2@1.10", "version:2@1.9", 1)
+	test("version:3@1.0", "version:2@9.9", 1)
+
+	_, err := CompareCIPDVersions("git_revision:ca6066d7097cf6a175b48c03d5e9c24c1ee0262f", "version:2@1.0")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a \"version:\" tag")
+	_, err = CompareCIPDVersions("version:2@1.0", "version:x@1.0")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid epoch")
+}
+
+func TestCIPDLag(t *testing.T) {
+	lag := CIPDLag(deps, map[string]string{
+		"infra/3pp/tools/ninja": "version:2@1.12.2",
+		"skia/tools/sk":         "git_revision:ca6066d7097cf6a175b48c03d5e9c24c1ee0262f",
+	})
+	assert.Equal(t, map[string]int{"infra/3pp/tools/ninja": 1}, lag)
+
+	entries := deps_parser.DepsEntries{
+		"current": {Id: "current", Version: "version:2@1.12.1"},
+		"ahead":   {Id: "ahead", Version: "version:2@1.13.0"},
+		"minor":   {Id: "minor", Version: "version:2@1.10.5"},
+		"epoch":   {Id: "epoch", Version: "version:1@1.12.1"},
+		"suffix":  {Id: "suffix", Version: "version:2@1.12.1.chromium.4"},
+	}
+	assert.Equal(t, map[string]int{
+		"current": 0,
+		"ahead":   0,
+		"minor":   2,
+		"epoch":   1,
+		"suffix":  1,
+	}, CIPDLag(entries, map[string]string{
+		"current": "version:2@1.12.1",
+		"ahead":   "version:2@1.12.1",
+		"minor":   "version:2@1.12.0",
+		"epoch":   "version:2@1.12.1",
+		"suffix":  "version:2@1.12.1.chromium.5",
+	}))
+}
+
+func TestAnnotatedEntry_Kind_MatchesClassifyVersion(t *testing.T) {
+	for _, e := range deps {
+		assert.Equal(t, ClassifyVersion(e.Version), Annotate(*e).Kind(), e.Id)
+	}
+}
+
+func TestVersionBytes(t *testing.T) {
+	for _, e := range deps {
+		assert.Equal(t, []byte(e.Version), VersionBytes(*e), e.Id)
+	}
+	assert.Empty(t, VersionBytes(deps_parser.DepsEntry{}))
+}
+
+// benchmarkLookups is the number of times each entry's kind is requested per
+// benchmark iteration, simulating a hot loop.
+const benchmarkLookups = 100
+
+func BenchmarkClassifyVersion_Repeated(b *testing.B) {
+	entries := sortedEntries(deps)
+	for i := 0; i < b.N; i++ {
+		for _, e := range entries {
+			for j := 0; j < benchmarkLookups; j++ {
+				_ = ClassifyVersion(e.Version)
+			}
+		}
+	}
+}
+
+func BenchmarkAnnotatedEntry_Kind_Repeated(b *testing.B) {
+	entries := sortedEntries(deps)
+	for i := 0; i < b.N; i++ {
+		for _, e := range entries {
+			a := Annotate(e)
+			for j := 0; j < benchmarkLookups; j++ {
+				_ = a.Kind()
+			}
+		}
+	}
+}